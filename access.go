@@ -0,0 +1,383 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+)
+
+const (
+	// defaultAccessExpire is used when AccessConfig.Expire is not set (1 day).
+	defaultAccessExpire int = 24
+	// defaultAccessMaxDuration is used when AccessConfig.MaxDuration is not set.
+	defaultAccessMaxDuration int = 8
+)
+
+var (
+	ErrAccessRequestsDisabled = errors.New("access requests are not enabled")
+	ErrRoleNotRequestable     = errors.New("role cannot be requested")
+	ErrInvalidAccessDuration  = errors.New("invalid access duration")
+	ErrAccessRequestClosed    = errors.New("access request is not pending")
+	ErrAccessRequestExpired   = errors.New("access request is expired")
+	ErrNotApprover            = errors.New("user cannot approve access request")
+	ErrSelfApproval           = errors.New("user cannot decide on own access request")
+	ErrNotRequester           = errors.New("access request belongs to another user")
+)
+
+// WithAccessRequestStore enables just-in-time access requests: users request a role for a limited time
+// and holders of approver roles approve or deny them, every state change is recorded.
+func WithAccessRequestStore(s store.AccessRequestStore) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.access = s
+	}
+}
+
+// RequestAccess creates a pending request of the user for a role listed in AccessConfig.Approvers.
+func (ctrl *controller) RequestAccess(ctx context.Context, req model.AccessRequestCreate) (int64, error) {
+	if ctrl.access == nil {
+		return 0, ErrAccessRequestsDisabled
+	}
+
+	maxDuration := ctrl.cfg.Access.MaxDuration
+	if maxDuration <= 0 {
+		maxDuration = defaultAccessMaxDuration
+	}
+	if req.Duration < time.Second || req.Duration > time.Hour*time.Duration(maxDuration) {
+		return 0, fmt.Errorf("%w %s, max is %dh", ErrInvalidAccessDuration, req.Duration, maxDuration)
+	}
+
+	expire := ctrl.cfg.Access.Expire
+	if expire <= 0 {
+		expire = defaultAccessExpire
+	}
+
+	ctx, err := ctrl.access.StartTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("access request store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.access.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback access request store transaction: %w", err))
+		}
+	}()
+
+	role, err := ctrl.role.FindOneByID(ctx, req.RoleID)
+	if err != nil {
+		return 0, fmt.Errorf("find role: %w", err)
+	}
+	if _, ok := ctrl.cfg.Access.Approvers[role.Name]; !ok {
+		return 0, fmt.Errorf("%w %q", ErrRoleNotRequestable, role.Name)
+	}
+
+	requestID, err := ctrl.access.InsertOne(ctx, model.AccessRequestDao{
+		UserID:    req.UserID,
+		RoleID:    req.RoleID,
+		RoleName:  role.Name,
+		Reason:    req.Reason,
+		Duration:  int64(req.Duration / time.Second),
+		Status:    model.AccessRequestPending,
+		ExpiresAt: time.Now().Add(time.Hour * time.Duration(expire)),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert access request: %w", err)
+	}
+
+	if err := ctrl.recordAccessEvent(ctx, requestID, req.UserID, model.AccessRequestPending, req.Reason); err != nil {
+		return 0, err
+	}
+
+	if err := ctrl.access.CommitTx(ctx); err != nil {
+		return 0, fmt.Errorf("commit access request transaction: %w", err)
+	}
+	return requestID, nil
+}
+
+// ApproveAccess approves the request and grants the role to the requester until the requested duration passes,
+// a permanent or a longer grant of the role is kept. Approver must currently hold one of the approver roles
+// of the requested role, roles of the approver token are not trusted, and cannot approve own requests.
+func (ctrl *controller) ApproveAccess(ctx context.Context, approver model.AuthMeta, requestID int64, comment string) error {
+	return ctrl.decideAccess(ctx, approver, requestID, model.AccessRequestApproved, comment)
+}
+
+// DenyAccess denies the request, approver requirements are the same as for ApproveAccess.
+func (ctrl *controller) DenyAccess(ctx context.Context, approver model.AuthMeta, requestID int64, comment string) error {
+	return ctrl.decideAccess(ctx, approver, requestID, model.AccessRequestDenied, comment)
+}
+
+func (ctrl *controller) decideAccess(
+	ctx context.Context,
+	approver model.AuthMeta,
+	requestID int64,
+	status model.AccessRequestStatus,
+	comment string,
+) error {
+	if ctrl.access == nil {
+		return ErrAccessRequestsDisabled
+	}
+
+	ctx, err := ctrl.access.StartTx(ctx)
+	if err != nil {
+		return fmt.Errorf("access request store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.access.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback access request store transaction: %w", err))
+		}
+	}()
+
+	req, err := ctrl.pendingAccessRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(req.ExpiresAt) {
+		// expiration is recorded even though the decision fails
+		if err := ctrl.expireAccessRequest(ctx, requestID); err != nil {
+			return err
+		}
+		if err := ctrl.access.CommitTx(ctx); err != nil {
+			return fmt.Errorf("commit access request transaction: %w", err)
+		}
+		return ErrAccessRequestExpired
+	}
+
+	if approver.UserID == req.UserID {
+		return ErrSelfApproval
+	}
+
+	role, err := ctrl.role.FindOneByID(ctx, req.RoleID)
+	if err != nil {
+		return fmt.Errorf("find role: %w", err)
+	}
+	// roles of the token may be stale, so the approver authority is resolved again
+	approverRoles, err := ctrl.role.ListUserRoles(ctx, approver.UserID)
+	if err != nil {
+		return fmt.Errorf("list approver roles: %w", err)
+	}
+	if !slices.ContainsFunc(approverRoles, func(r model.RoleDao) bool {
+		return slices.Contains(ctrl.cfg.Access.Approvers[role.Name], r.Name)
+	}) {
+		return fmt.Errorf("%w for role %q", ErrNotApprover, role.Name)
+	}
+
+	decided, err := ctrl.access.Decide(ctx, requestID, status, approver.UserID)
+	if err != nil {
+		return fmt.Errorf("decide access request: %w", err)
+	}
+	if !decided {
+		return ErrAccessRequestClosed
+	}
+
+	if status == model.AccessRequestApproved {
+		if err := ctrl.grantAccess(ctx, req, approver.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := ctrl.recordAccessEvent(ctx, requestID, approver.UserID, status, comment); err != nil {
+		return err
+	}
+
+	if err := ctrl.access.CommitTx(ctx); err != nil {
+		return fmt.Errorf("commit access request transaction: %w", err)
+	}
+	return nil
+}
+
+// grantAccess grants the requested role for the requested duration. A permanent or a longer grant
+// the user already holds is kept, so approval never shortens access.
+func (ctrl *controller) grantAccess(ctx context.Context, req model.AccessRequestDao, approverID int64) error {
+	expiresAt := time.Now().Add(time.Second * time.Duration(req.Duration))
+
+	roles, err := ctrl.role.ListUserRoles(ctx, req.UserID)
+	if err != nil {
+		return fmt.Errorf("list user roles: %w", err)
+	}
+	for _, role := range roles {
+		if role.ID == req.RoleID && (role.ExpiresAt == nil || !role.ExpiresAt.Before(expiresAt)) {
+			return nil
+		}
+	}
+
+	grant := model.RoleGrant{
+		UserID:    req.UserID,
+		RoleID:    req.RoleID,
+		ExpiresAt: expiresAt,
+		GrantedBy: approverID,
+		Reason:    req.Reason,
+	}
+	if err := ctrl.user.SetRoleUntil(ctx, grant); err != nil {
+		return fmt.Errorf("set role until: %w", err)
+	}
+	return nil
+}
+
+// CancelAccess cancels a pending request on behalf of the requester.
+func (ctrl *controller) CancelAccess(ctx context.Context, userID, requestID int64) error {
+	if ctrl.access == nil {
+		return ErrAccessRequestsDisabled
+	}
+
+	ctx, err := ctrl.access.StartTx(ctx)
+	if err != nil {
+		return fmt.Errorf("access request store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.access.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback access request store transaction: %w", err))
+		}
+	}()
+
+	req, err := ctrl.pendingAccessRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if req.UserID != userID {
+		return ErrNotRequester
+	}
+
+	decided, err := ctrl.access.Decide(ctx, requestID, model.AccessRequestCancelled, userID)
+	if err != nil {
+		return fmt.Errorf("cancel access request: %w", err)
+	}
+	if !decided {
+		return ErrAccessRequestClosed
+	}
+
+	if err := ctrl.recordAccessEvent(ctx, requestID, userID, model.AccessRequestCancelled, ""); err != nil {
+		return err
+	}
+
+	if err := ctrl.access.CommitTx(ctx); err != nil {
+		return fmt.Errorf("commit access request transaction: %w", err)
+	}
+	return nil
+}
+
+// ExpireAccessRequests marks pending requests past their decision deadline as expired and returns their number.
+// Such requests cannot be approved anyway, it is meant to be called periodically to keep the audit log complete.
+func (ctrl *controller) ExpireAccessRequests(ctx context.Context) (int, error) {
+	if ctrl.access == nil {
+		return 0, ErrAccessRequestsDisabled
+	}
+
+	ctx, err := ctrl.access.StartTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("access request store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.access.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback access request store transaction: %w", err))
+		}
+	}()
+
+	requestIDs, err := ctrl.access.ExpirePending(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("expire access requests: %w", err)
+	}
+	for _, requestID := range requestIDs {
+		if err := ctrl.recordAccessEvent(ctx, requestID, 0, model.AccessRequestExpired, ""); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := ctrl.access.CommitTx(ctx); err != nil {
+		return 0, fmt.Errorf("commit access request transaction: %w", err)
+	}
+	return len(requestIDs), nil
+}
+
+// pendingAccessRequest finds the request and returns ErrAccessRequestClosed if it is not pending.
+func (ctrl *controller) pendingAccessRequest(ctx context.Context, requestID int64) (model.AccessRequestDao, error) {
+	req, err := ctrl.access.FindOneByID(ctx, requestID)
+	if err != nil {
+		return req, fmt.Errorf("find access request: %w", err)
+	}
+	if req.Status != model.AccessRequestPending {
+		return req, fmt.Errorf("%w: %s", ErrAccessRequestClosed, req.Status)
+	}
+	return req, nil
+}
+
+// expireAccessRequest marks the pending request expired.
+func (ctrl *controller) expireAccessRequest(ctx context.Context, requestID int64) error {
+	decided, err := ctrl.access.Decide(ctx, requestID, model.AccessRequestExpired, 0)
+	if err != nil {
+		return fmt.Errorf("expire access request: %w", err)
+	}
+	if !decided {
+		return ErrAccessRequestClosed
+	}
+	return ctrl.recordAccessEvent(ctx, requestID, 0, model.AccessRequestExpired, "")
+}
+
+func (ctrl *controller) recordAccessEvent(
+	ctx context.Context,
+	requestID, actorID int64,
+	status model.AccessRequestStatus,
+	comment string,
+) error {
+	event := model.AccessRequestEventDao{
+		RequestID: requestID,
+		ActorID:   actorID,
+		Status:    status,
+		Comment:   comment,
+	}
+	if err := ctrl.access.InsertEvent(ctx, event); err != nil {
+		return fmt.Errorf("record access request event: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListPendingAccessRequests(ctx context.Context) ([]model.AccessRequestDto, error) {
+	if ctrl.access == nil {
+		return nil, ErrAccessRequestsDisabled
+	}
+
+	requestsDB, err := ctrl.access.ListPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pending access requests: %w", err)
+	}
+	return accessRequestsToDto(requestsDB), nil
+}
+
+func (ctrl *controller) ListUserAccessRequests(ctx context.Context, userID int64) ([]model.AccessRequestDto, error) {
+	if ctrl.access == nil {
+		return nil, ErrAccessRequestsDisabled
+	}
+
+	requestsDB, err := ctrl.access.ListUserRequests(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user access requests: %w", err)
+	}
+	return accessRequestsToDto(requestsDB), nil
+}
+
+func (ctrl *controller) ListAccessRequestEvents(ctx context.Context, requestID int64) ([]model.AccessRequestEventDto, error) {
+	if ctrl.access == nil {
+		return nil, ErrAccessRequestsDisabled
+	}
+
+	eventsDB, err := ctrl.access.ListEvents(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("list access request events: %w", err)
+	}
+
+	events := make([]model.AccessRequestEventDto, 0, len(eventsDB))
+	for _, event := range eventsDB {
+		events = append(events, event.ToDto())
+	}
+	return events, nil
+}
+
+func accessRequestsToDto(requestsDB []model.AccessRequestDao) []model.AccessRequestDto {
+	requests := make([]model.AccessRequestDto, 0, len(requestsDB))
+	for _, req := range requestsDB {
+		requests = append(requests, req.ToDto())
+	}
+	return requests
+}
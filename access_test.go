@@ -0,0 +1,132 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestApproveAccess(t *testing.T) {
+	ctx := context.Background()
+
+	access := &accessStoreStub{}
+	ctrl := newTestController(t, AuthConfig{Access: AccessConfig{Approvers: map[string][]string{"oncall": {"lead"}}}},
+		WithAccessRequestStore(access))
+
+	roles := createRoles(t, ctrl, "oncall", "lead")
+	oncallID, leadID := roles["oncall"], roles["lead"]
+	requester := registerUser(t, ctrl, "user")
+	approver := registerUser(t, ctrl, "lead")
+
+	pending := model.AccessRequestDao{
+		ID:        1,
+		UserID:    requester.Meta.UserID,
+		RoleID:    oncallID,
+		RoleName:  "oncall",
+		Duration:  int64(time.Hour / time.Second),
+		Status:    model.AccessRequestPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	access.req = pending
+	if err := ctrl.ApproveAccess(ctx, approver.Meta, 1, ""); !errors.Is(err, ErrNotApprover) {
+		t.Fatalf("approver without the role: got %v, want ErrNotApprover", err)
+	}
+
+	// the lead role is claimed by the token, but is not held by the approver
+	forged := approver.Meta
+	forged.Roles = append(forged.Roles, model.RoleDto{ID: leadID, Name: "lead"})
+	if err := ctrl.ApproveAccess(ctx, forged, 1, ""); !errors.Is(err, ErrNotApprover) {
+		t.Fatalf("approve with token roles: got %v, want ErrNotApprover", err)
+	}
+	if access.req.Status != model.AccessRequestPending {
+		t.Fatalf("request is %s after failed approval", access.req.Status)
+	}
+
+	if err := ctrl.SetRole(ctx, approver.Meta.UserID, leadID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ctrl.SetRole(ctx, requester.Meta.UserID, leadID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.ApproveAccess(ctx, requester.Meta, 1, ""); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("own request: got %v, want ErrSelfApproval", err)
+	}
+
+	// a permanent grant is not shortened by the approval
+	if err := ctrl.SetRole(ctx, requester.Meta.UserID, oncallID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.ApproveAccess(ctx, approver.Meta, 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if expiresAt := roleExpiry(t, ctrl, requester.Meta.UserID, oncallID); expiresAt != nil {
+		t.Fatalf("permanent grant expires at %v", expiresAt)
+	}
+
+	// a longer grant is kept, a shorter one is extended
+	if err := ctrl.RemoveRole(ctx, requester.Meta.UserID, oncallID); err != nil {
+		t.Fatal(err)
+	}
+	longer := time.Now().Add(48 * time.Hour)
+	for _, until := range []time.Time{longer, time.Now().Add(time.Minute)} {
+		if err := ctrl.SetRoleUntil(ctx, model.RoleGrant{UserID: requester.Meta.UserID, RoleID: oncallID, ExpiresAt: until}); err != nil {
+			t.Fatal(err)
+		}
+		access.req = pending
+		if err := ctrl.ApproveAccess(ctx, approver.Meta, 1, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		expiresAt := roleExpiry(t, ctrl, requester.Meta.UserID, oncallID)
+		want := time.Now().Add(time.Hour)
+		if until.After(want) {
+			want = until
+		}
+		if expiresAt == nil || expiresAt.Sub(want).Abs() > time.Minute {
+			t.Fatalf("grant expires at %v, want %v", expiresAt, want)
+		}
+	}
+
+	// the request is already approved
+	for name, decide := range map[string]func(context.Context, model.AuthMeta, int64, string) error{
+		"approve": ctrl.ApproveAccess,
+		"deny":    ctrl.DenyAccess,
+	} {
+		if err := decide(ctx, approver.Meta, 1, ""); !errors.Is(err, ErrAccessRequestClosed) {
+			t.Errorf("%s decided request: got %v, want ErrAccessRequestClosed", name, err)
+		}
+	}
+	if access.req.Status != model.AccessRequestApproved {
+		t.Errorf("decided request is %s, want approved", access.req.Status)
+	}
+
+	access.req = pending
+	access.req.ExpiresAt = time.Now().Add(-time.Second)
+	if err := ctrl.ApproveAccess(ctx, approver.Meta, 1, ""); !errors.Is(err, ErrAccessRequestExpired) {
+		t.Fatalf("expired request: got %v, want ErrAccessRequestExpired", err)
+	}
+	if access.req.Status != model.AccessRequestExpired {
+		t.Errorf("expired request is %s, want expired", access.req.Status)
+	}
+}
+
+func roleExpiry(t *testing.T, ctrl *controller, userID, roleID int64) *time.Time {
+	t.Helper()
+
+	roles, err := ctrl.role.ListUserRoles(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role.ExpiresAt
+		}
+	}
+	t.Fatalf("user %d doesn't hold role %d", userID, roleID)
+	return nil
+}
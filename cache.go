@@ -0,0 +1,75 @@
+package authgo
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// tokenCache is a bounded LRU cache of verified token claims keyed by token hash.
+// Entries are bound to the keyring version, so retiring a key invalidates them.
+type tokenCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List
+}
+
+type tokenCacheEntry struct {
+	key     [sha256.Size]byte
+	claims  *accessClaims
+	version uint64
+}
+
+func newTokenCache(size int) *tokenCache {
+	return &tokenCache{
+		size:    size,
+		entries: make(map[[sha256.Size]byte]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// get returns cached claims of the token, or nil if token is not cached or was verified by another keyring version.
+func (c *tokenCache) get(token string, version uint64) *accessClaims {
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*tokenCacheEntry)
+	if entry.version != version {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.claims
+}
+
+// put caches claims of the verified token, evicting the least recently used entry if cache is full.
+func (c *tokenCache) put(token string, claims *accessClaims, version uint64) {
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &tokenCacheEntry{key: key, claims: claims, version: version}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*tokenCacheEntry).key)
+	}
+
+	c.entries[key] = c.order.PushFront(&tokenCacheEntry{key: key, claims: claims, version: version})
+}
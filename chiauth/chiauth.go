@@ -0,0 +1,72 @@
+// Package chiauth provides chi middlewares that authenticate requests with authgo.
+// chi uses standard net/http middlewares, so this package is a thin layer over authgo net/http middlewares.
+package chiauth
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+)
+
+// Option configures middlewares.
+type Option = authgo.HTTPOption
+
+// WithErrorResponder sets a function that writes responses for unauthenticated and forbidden requests.
+func WithErrorResponder(f authgo.ErrorResponder) Option {
+	return authgo.WithErrorResponder(f)
+}
+
+// WithGuardOptions configures authgo.Guard used by middlewares, e.g. enables authgo.WithCookieTransport.
+func WithGuardOptions(opts ...authgo.GuardOption) Option {
+	return authgo.WithGuardOptions(opts...)
+}
+
+// Authenticate requires a valid access token and puts model.AuthMeta into request context,
+// which can be read with MetaFromRequest.
+func Authenticate(m authgo.Middleware, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.Authenticate(m, opts...)
+}
+
+// RequireRole requires the user to have the role, must be used after Authenticate.
+func RequireRole(m authgo.Middleware, role string, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.RequireRole(m, role, opts...)
+}
+
+// RequireAnyRole requires the user to have at least one of the roles, must be used after Authenticate.
+func RequireAnyRole(m authgo.Middleware, roles []string, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.RequireAnyRole(m, roles, opts...)
+}
+
+// RequireAllRoles requires the user to have every role, must be used after Authenticate.
+func RequireAllRoles(m authgo.Middleware, roles []string, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.RequireAllRoles(m, roles, opts...)
+}
+
+// RequireRoleExpr requires roles of the user to satisfy the expression, must be used after Authenticate.
+func RequireRoleExpr(m authgo.Middleware, expr *authgo.RoleExpr, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.RequireRoleExpr(m, expr, opts...)
+}
+
+// RequirePermission requires the user to have the permission, must be used after Authenticate.
+func RequirePermission(m authgo.Middleware, permission string, opts ...Option) func(http.Handler) http.Handler {
+	return authgo.RequirePermission(m, permission, opts...)
+}
+
+// Protected returns middlewares for a route group that requires authentication and,
+// if roles are provided, at least one of them:
+//
+//	r.With(chiauth.Protected(m, "admin")...).Get("/admin", handler)
+func Protected(m authgo.Middleware, roles ...string) chi.Middlewares {
+	middlewares := chi.Middlewares{Authenticate(m)}
+	if len(roles) > 0 {
+		middlewares = append(middlewares, RequireAnyRole(m, roles))
+	}
+	return middlewares
+}
+
+// MetaFromRequest returns model.AuthMeta put into request context by Authenticate.
+func MetaFromRequest(r *http.Request) (model.AuthMeta, bool) {
+	return authgo.MetaFromContext(r.Context())
+}
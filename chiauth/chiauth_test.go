@@ -0,0 +1,28 @@
+package chiauth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/guardtest"
+)
+
+func TestConformance(t *testing.T) {
+	guardtest.Run(t, func(t *testing.T, m authgo.Middleware, opts ...authgo.GuardOption) http.Handler {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			meta, _ := MetaFromRequest(r)
+			guardtest.WriteUserID(w, meta)
+		}
+
+		r := chi.NewRouter()
+		r.Group(func(r chi.Router) {
+			r.Use(Authenticate(m, WithGuardOptions(opts...)))
+			r.HandleFunc(guardtest.PathAuth, handler)
+			r.With(RequireRole(m, guardtest.Role)).HandleFunc(guardtest.PathRole, handler)
+			r.With(RequirePermission(m, guardtest.Permission)).HandleFunc(guardtest.PathPermission, handler)
+		})
+		return r
+	})
+}
@@ -0,0 +1,188 @@
+package authgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yogenyslav/authgo/model"
+)
+
+// accessClaims are claims of access token. Custom claims are stored as top-level claims
+// and are collected into Extra when token is parsed.
+type accessClaims struct {
+	jwt.RegisteredClaims
+	SessionID      string          `json:"sid,omitempty"`
+	OrgID          int64           `json:"org,omitempty"`
+	Roles          []model.RoleDto `json:"roles"`
+	Permissions    []string        `json:"perms,omitempty"`
+	OrgRoles       []model.RoleDto `json:"org_roles,omitempty"`
+	OrgPermissions []string        `json:"org_perms,omitempty"`
+	Extra          map[string]any  `json:"-"`
+}
+
+// newAccessClaims creates claims from meta, custom claims are validated not to override reserved ones.
+func newAccessClaims(meta model.AuthMeta) (*accessClaims, error) {
+	for name := range meta.Extra {
+		if _, ok := reservedClaims[name]; ok {
+			return nil, fmt.Errorf("custom claim %q: %w", name, ErrReservedClaim)
+		}
+	}
+
+	return &accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: strconv.FormatInt(meta.UserID, 10),
+			ID:      meta.TokenID,
+		},
+		SessionID:      meta.SessionID,
+		OrgID:          meta.OrgID,
+		Roles:          meta.Roles,
+		Permissions:    meta.Permissions,
+		OrgRoles:       meta.OrgRoles,
+		OrgPermissions: meta.OrgPermissions,
+		Extra:          meta.Extra,
+	}, nil
+}
+
+// toMeta converts claims into AuthMeta, slices and maps are copied, so claims can be safely reused.
+func (c *accessClaims) toMeta() (model.AuthMeta, error) {
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return model.AuthMeta{}, fmt.Errorf("%w: sub: %w", ErrTokenMalformed, err)
+	}
+
+	return model.AuthMeta{
+		UserID:         userID,
+		SessionID:      c.SessionID,
+		OrgID:          c.OrgID,
+		TokenID:        c.ID,
+		Roles:          slices.Clone(c.Roles),
+		Permissions:    slices.Clone(c.Permissions),
+		OrgRoles:       slices.Clone(c.OrgRoles),
+		OrgPermissions: slices.Clone(c.OrgPermissions),
+		Extra:          maps.Clone(c.Extra),
+	}, nil
+}
+
+// MarshalJSON encodes custom claims alongside the reserved ones.
+func (c accessClaims) MarshalJSON() ([]byte, error) {
+	type plain accessClaims
+
+	data, err := json.Marshal(plain(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	extra, err := json.Marshal(c.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	// merge two json objects: {...reserved} + {...extra}
+	merged := make([]byte, 0, len(data)+len(extra))
+	merged = append(merged, data[:len(data)-1]...)
+	if len(data) > 2 {
+		merged = append(merged, ',')
+	}
+	merged = append(merged, extra[1:]...)
+
+	return merged, nil
+}
+
+// UnmarshalJSON decodes reserved claims into typed fields and the rest into Extra.
+// The payload is read in a single pass, only unknown claims are decoded into generic values,
+// their integer numbers are kept as int64, so ids above 2^53 are not rounded.
+func (c *accessClaims) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("claims: expected object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+
+		var target any
+		switch name {
+		case "iss":
+			target = &c.Issuer
+		case "sub":
+			target = &c.Subject
+		case "aud":
+			target = &c.Audience
+		case "exp":
+			target = &c.ExpiresAt
+		case "nbf":
+			target = &c.NotBefore
+		case "iat":
+			target = &c.IssuedAt
+		case "jti":
+			target = &c.ID
+		case "sid":
+			target = &c.SessionID
+		case "org":
+			target = &c.OrgID
+		case "roles":
+			target = &c.Roles
+		case "perms":
+			target = &c.Permissions
+		case "org_roles":
+			target = &c.OrgRoles
+		case "org_perms":
+			target = &c.OrgPermissions
+		default:
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return fmt.Errorf("claim %q: %w", name, err)
+			}
+			if c.Extra == nil {
+				c.Extra = make(map[string]any)
+			}
+			c.Extra[name] = claimNumbers(v)
+			continue
+		}
+
+		if err := dec.Decode(target); err != nil {
+			return fmt.Errorf("claim %q: %w", name, err)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// claimNumbers converts json.Number in the decoded claim value to int64 if it is an integer, otherwise to float64.
+func claimNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = claimNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = claimNumbers(item)
+		}
+	}
+	return v
+}
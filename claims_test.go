@@ -0,0 +1,51 @@
+package authgo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestAccessClaimsJSON(t *testing.T) {
+	claims, err := newAccessClaims(model.AuthMeta{
+		UserID:         7,
+		SessionID:      "sid",
+		OrgID:          3,
+		TokenID:        "jti",
+		Roles:          []model.RoleDto{{ID: 1, Name: model.DefaultRole}},
+		Permissions:    []string{"users:read"},
+		OrgRoles:       []model.RoleDto{{ID: 2, Name: "admin"}},
+		OrgPermissions: []string{"org:manage"},
+		Extra: map[string]any{
+			"tenant":   "acme",
+			"limits":   map[string]any{"rps": int64(10), "burst": 1.5},
+			"accounts": []any{int64(1<<53 + 1), int64(-3)},
+			"quota":    2.5e-3,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got accessClaims
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, claims) {
+		t.Fatalf("round trip mismatch:\ngot  %+v\nwant %+v", got, *claims)
+	}
+
+	for _, payload := range []string{`[]`, `{"sub":1}`, `{"tenant":}`, `{"sub":"1"`} {
+		var c accessClaims
+		if err := json.Unmarshal([]byte(payload), &c); err == nil {
+			t.Errorf("payload %s: expected error", payload)
+		}
+	}
+}
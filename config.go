@@ -5,12 +5,95 @@ import "github.com/yogenyslav/authgo/store/postgres"
 // AuthConfig is a top-level config for authgo package that holds other nested configs.
 type AuthConfig struct {
 	Jwt      JwtConfig       `yaml:"jwt"`
+	Session  SessionConfig   `yaml:"session"`
+	Invite   InviteConfig    `yaml:"invite"`
+	Access   AccessConfig    `yaml:"access"`
 	Postgres postgres.Config `yaml:"postgres"`
 }
 
 // JwtConfig is a config for jwt module.
 type JwtConfig struct {
+	// Algorithm is a JWS algorithm used to sign tokens (HS256 by default).
+	// Supported families are HMAC (HS*), RSA (RS*, PS*), ECDSA (ES*) and Ed25519 (EdDSA).
+	Algorithm string `yaml:"algorithm"`
+	// Secret is a shared key for HMAC algorithms.
+	Secret string `yaml:"secret"`
+	// PrivateKey is a PEM encoded private key for asymmetric algorithms, required only to sign tokens.
+	PrivateKey string `yaml:"private_key"`
+	// PublicKey is a PEM encoded public key for asymmetric algorithms, derived from PrivateKey if empty.
+	PublicKey string `yaml:"public_key"`
+	// KeyID is an id (kid) of the key defined by the fields above ("default" if empty).
+	KeyID string `yaml:"key_id"`
+	// Keys are additional keys, e.g. the previous keys that are still accepted during rotation.
+	Keys []KeyConfig `yaml:"keys"`
+	// ActiveKey is an id of the key used to sign new tokens.
+	ActiveKey string `yaml:"active_key"`
+	// Expire is a lifetime of access token in hours.
+	Expire int `yaml:"expire"`
+	// Issuer is put into iss claim and required to match when set.
+	Issuer string `yaml:"issuer"`
+	// Audience is put into aud claim, token must be intended for at least one of them when set.
+	Audience []string `yaml:"audience"`
+	// NotBefore is a delay in seconds after issuing before token becomes valid (nbf claim).
+	NotBefore int `yaml:"not_before"`
+	// Leeway is an allowed clock skew in seconds for exp, nbf and iat checks.
+	Leeway int `yaml:"leeway"`
+	// RefreshExpire is a lifetime of refresh token in hours.
+	RefreshExpire int `yaml:"refresh_expire"`
+	// Encryption enables wrapping of signed tokens into JWE, the legacy scalar form with a raw AES key is still accepted.
+	Encryption EncryptionConfig `yaml:"encryption"`
+}
+
+// SessionConfig is a config for server-side auth sessions.
+type SessionConfig struct {
+	// Expire is a lifetime of session in hours.
+	Expire int `yaml:"expire"`
+}
+
+// InviteConfig is a config for invitations.
+type InviteConfig struct {
+	// Expire is a lifetime of invite in hours.
+	Expire int `yaml:"expire"`
+}
+
+// AccessConfig is a config for just-in-time access requests.
+type AccessConfig struct {
+	// Approvers maps names of requestable roles to names of roles allowed to approve requests for them.
+	// Roles which are not listed cannot be requested.
+	Approvers map[string][]string `yaml:"approvers"`
+	// Expire is a time in hours to decide on a request before it expires.
+	Expire int `yaml:"expire"`
+	// MaxDuration is a maximum lifetime of a granted role in hours.
+	MaxDuration int `yaml:"max_duration"`
+}
+
+// KeyConfig describes one key of the keyring.
+type KeyConfig struct {
+	ID         string `yaml:"id"`
+	Algorithm  string `yaml:"algorithm"`
 	Secret     string `yaml:"secret"`
-	Expire     int    `yaml:"expire"`
-	Encryption string `yaml:"encryption"`
+	PrivateKey string `yaml:"private_key"`
+	PublicKey  string `yaml:"public_key"`
+}
+
+// EncryptionConfig is a config for token encryption with compact JWE.
+type EncryptionConfig struct {
+	// Algorithm is a JWE key management algorithm: dir, A128KW, A192KW, A256KW, A128GCMKW, A192GCMKW, A256GCMKW,
+	// RSA-OAEP, RSA-OAEP-256, ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A192KW or ECDH-ES+A256KW.
+	// Defaults to dir if Key or Passphrase is set, otherwise encryption is disabled.
+	Algorithm string `yaml:"algorithm"`
+	// Content is a content encryption algorithm (A256GCM by default).
+	Content string `yaml:"content"`
+	// Key is a base64 encoded symmetric key for dir and AES key wrap algorithms.
+	Key string `yaml:"key"`
+	// Passphrase is used to derive symmetric key with PBKDF2 instead of Key.
+	Passphrase string `yaml:"passphrase"`
+	// Salt is a PBKDF2 salt for Passphrase, required with it: a random string of at least 16 bytes unique per deployment.
+	Salt string `yaml:"salt"`
+	// Iterations is a number of PBKDF2 iterations for Passphrase (600000 by default).
+	Iterations int `yaml:"iterations"`
+	// PrivateKey is a PEM encoded RSA or EC private key, required only to decrypt tokens.
+	PrivateKey string `yaml:"private_key"`
+	// PublicKey is a PEM encoded RSA or EC public key, derived from PrivateKey if empty.
+	PublicKey string `yaml:"public_key"`
 }
@@ -15,14 +15,50 @@ var (
 
 // controller provides methods to manipulate with user and its roles.
 type controller struct {
-	cfg  AuthConfig
-	user store.UserStore
-	role store.RoleStore
-	jwt  *jwtProvider
+	cfg        AuthConfig
+	user       store.UserStore
+	role       store.RoleStore
+	refresh    store.RefreshTokenStore
+	session    store.SessionStore
+	revocation store.RevocationList
+	org        store.OrgStore
+	invite     store.InviteStore
+	access     store.AccessRequestStore
+	keys       *Keyring
+	enricher   ClaimsEnricher
+	jwt        *jwtProvider
+}
+
+// ControllerOption configures optional subsystems of the controller.
+type ControllerOption func(ctrl *controller)
+
+// WithRefreshTokenStore enables issuing of refresh tokens persisted in the provided store.
+func WithRefreshTokenStore(s store.RefreshTokenStore) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.refresh = s
+	}
+}
+
+// WithKeyring makes controller sign tokens with the shared keyring instead of keys from config.
+func WithKeyring(k *Keyring) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.keys = k
+	}
+}
+
+// ClaimsEnricher returns custom claims to put into the access token of the user, e.g. tenant id or feature flags.
+// Custom claims are available in AuthMeta.Extra after RequireAuth.
+type ClaimsEnricher func(ctx context.Context, meta model.AuthMeta) (map[string]any, error)
+
+// WithClaimsEnricher sets a hook that adds custom claims to every issued access token.
+func WithClaimsEnricher(e ClaimsEnricher) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.enricher = e
+	}
 }
 
 // NewAuthController is a constructor for Controller.
-func NewAuthController(cfg AuthConfig, u store.UserStore, r store.RoleStore) (*controller, error) {
+func NewAuthController(cfg AuthConfig, u store.UserStore, r store.RoleStore, opts ...ControllerOption) (*controller, error) {
 	if err := r.ApplyMigrations(); err != nil {
 		return nil, fmt.Errorf("role schema: %w", err)
 	}
@@ -31,14 +67,64 @@ func NewAuthController(cfg AuthConfig, u store.UserStore, r store.RoleStore) (*c
 		return nil, fmt.Errorf("user schema: %w", err)
 	}
 
-	jwt := newJwtProvider(cfg.Jwt)
-
-	return &controller{
+	ctrl := &controller{
 		cfg:  cfg,
 		user: u,
 		role: r,
-		jwt:  jwt,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(ctrl)
+	}
+
+	jwt, err := newJwtProvider(cfg.Jwt, ctrl.keys)
+	if err != nil {
+		return nil, fmt.Errorf("jwt provider: %w", err)
+	}
+	ctrl.jwt = jwt
+	ctrl.keys = jwt.keys
+
+	if ctrl.refresh != nil {
+		if err := ctrl.refresh.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("refresh token schema: %w", err)
+		}
+	}
+
+	if ctrl.session != nil {
+		if err := ctrl.session.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("session schema: %w", err)
+		}
+	}
+
+	if ctrl.org != nil {
+		if err := ctrl.org.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("organization schema: %w", err)
+		}
+	}
+
+	if ctrl.invite != nil {
+		if err := ctrl.invite.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("invite schema: %w", err)
+		}
+	}
+
+	if ctrl.access != nil {
+		if err := ctrl.access.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("access request schema: %w", err)
+		}
+	}
+
+	if m, ok := ctrl.revocation.(migrator); ok {
+		if err := m.ApplyMigrations(); err != nil {
+			return nil, fmt.Errorf("revocation list schema: %w", err)
+		}
+	}
+
+	return ctrl, nil
+}
+
+// Keyring returns the keyring used to sign tokens, so keys can be rotated at runtime.
+func (ctrl *controller) Keyring() *Keyring {
+	return ctrl.keys
 }
 
 func (ctrl *controller) Login(ctx context.Context, req model.UserLogin) (model.AuthResp, error) {
@@ -50,33 +136,20 @@ func (ctrl *controller) Login(ctx context.Context, req model.UserLogin) (model.A
 	}
 
 	if !verifyPassword(user.HashPassword, req.Password) {
-		return resp, fmt.Errorf("verify password: %w", err)
+		return resp, fmt.Errorf("verify password: %w", ErrInvalidPassword)
 	}
 
-	rolesDB, err := ctrl.role.ListUserRoles(ctx, user.ID)
+	meta, err := ctrl.authMeta(ctx, user.ID, req.OrgID)
 	if err != nil {
-		return resp, fmt.Errorf("list user roles: %w", err)
+		return resp, err
 	}
 
-	roles := make([]model.RoleDto, 0, len(rolesDB))
-	for _, role := range rolesDB {
-		roles = append(roles, role.ToDto())
-	}
-
-	meta := model.AuthMeta{
-		UserID: user.ID,
-		Roles:  roles,
-	}
-	accessToken, err := ctrl.jwt.createAccessToken(meta)
+	meta.SessionID, err = ctrl.startSession(ctx, user.ID, req.Client)
 	if err != nil {
-		return resp, fmt.Errorf("create access token: %w", err)
+		return resp, fmt.Errorf("start session: %w", err)
 	}
 
-	resp.Meta = meta
-	resp.Token = accessToken
-	resp.Type = typeBearerToken
-
-	return resp, nil
+	return ctrl.issueTokens(ctx, meta, "")
 }
 
 func (ctrl *controller) Register(ctx context.Context, req model.UserRegister) (model.AuthResp, error) {
@@ -97,42 +170,126 @@ func (ctrl *controller) Register(ctx context.Context, req model.UserRegister) (m
 		}
 	}()
 
-	user := model.UserDao{
+	userID, err := ctrl.createUser(ctx, model.UserDao{
 		Email:        req.Email,
 		HashPassword: hashedPassword,
 		Username:     req.Username,
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
 		MiddleName:   req.MiddleName,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	meta, err := ctrl.authMeta(ctx, userID, 0)
+	if err != nil {
+		return resp, err
+	}
+
+	meta.SessionID, err = ctrl.startSession(ctx, userID, req.Client)
+	if err != nil {
+		return resp, fmt.Errorf("start session: %w", err)
+	}
+
+	resp, err = ctrl.issueTokens(ctx, meta, "")
+	if err != nil {
+		return resp, err
 	}
+
+	if err = ctrl.user.CommitTx(ctx); err != nil {
+		return resp, fmt.Errorf("commit user transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// createUser inserts the user with the default role and returns its id.
+func (ctrl *controller) createUser(ctx context.Context, user model.UserDao) (int64, error) {
 	userID, err := ctrl.user.InsertOne(ctx, user)
 	if err != nil {
-		return resp, fmt.Errorf("insert user: %w", err)
+		return 0, fmt.Errorf("insert user: %w", err)
 	}
 
 	role, err := ctrl.role.FindOneByName(ctx, model.DefaultRole)
 	if err != nil {
-		return resp, fmt.Errorf("find role by name: %w", err)
+		return 0, fmt.Errorf("find role by name: %w", err)
 	}
 
 	if err = ctrl.user.SetRole(ctx, userID, role.ID); err != nil {
-		return resp, fmt.Errorf("set role: %w", err)
+		return 0, fmt.Errorf("set role: %w", err)
+	}
+
+	return userID, nil
+}
+
+// authMeta collects data about user that is put into the access token.
+// Roles and permissions of the active organization are kept apart from the global ones.
+func (ctrl *controller) authMeta(ctx context.Context, userID, orgID int64) (model.AuthMeta, error) {
+	rolesDB, err := ctrl.role.ListUserRoles(ctx, userID)
+	if err != nil {
+		return model.AuthMeta{}, fmt.Errorf("list user roles: %w", err)
+	}
+
+	roles := make([]model.RoleDto, 0, len(rolesDB))
+	for _, role := range rolesDB {
+		roles = append(roles, role.ToDto())
+	}
+
+	permissionsDB, err := ctrl.role.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return model.AuthMeta{}, fmt.Errorf("list user permissions: %w", err)
+	}
+
+	permissions := make([]string, 0, len(permissionsDB))
+	for _, permission := range permissionsDB {
+		permissions = append(permissions, permission.Name)
 	}
 
 	meta := model.AuthMeta{
-		UserID: userID,
-		Roles: []model.RoleDto{{
-			ID:   userID,
-			Name: model.DefaultRole,
-		}},
+		UserID:      userID,
+		Roles:       roles,
+		Permissions: permissions,
+	}
+	if orgID != 0 {
+		if err := ctrl.addOrgMeta(ctx, &meta, orgID); err != nil {
+			return model.AuthMeta{}, err
+		}
 	}
+
+	return meta, nil
+}
+
+// issueTokens creates an access token and, if enabled, a refresh token within the given family.
+// Empty familyID starts a new family.
+func (ctrl *controller) issueTokens(ctx context.Context, meta model.AuthMeta, familyID string) (model.AuthResp, error) {
+	var (
+		resp model.AuthResp
+		err  error
+	)
+
+	if ctrl.enricher != nil {
+		meta.Extra, err = ctrl.enricher(ctx, meta)
+		if err != nil {
+			return resp, fmt.Errorf("enrich claims: %w", err)
+		}
+	}
+
+	meta.TokenID, err = generateToken(tokenIDSize)
+	if err != nil {
+		return resp, fmt.Errorf("generate token id: %w", err)
+	}
+
 	accessToken, err := ctrl.jwt.createAccessToken(meta)
 	if err != nil {
 		return resp, fmt.Errorf("create access token: %w", err)
 	}
 
-	if err = ctrl.user.CommitTx(ctx); err != nil {
-		return resp, fmt.Errorf("commit user transaction: %w", err)
+	if ctrl.refresh != nil {
+		resp.RefreshToken, err = ctrl.issueRefreshToken(ctx, meta, familyID)
+		if err != nil {
+			return resp, fmt.Errorf("create refresh token: %w", err)
+		}
 	}
 
 	resp.Meta = meta
@@ -0,0 +1,50 @@
+package authgo
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestRegisterMeta(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := newTestController(t, AuthConfig{})
+
+	defaultRole, err := ctrl.role.FindOneByName(ctx, model.DefaultRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readerID, err := ctrl.CreateRole(ctx, model.RoleCreate{Name: "reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.AddInheritedRole(ctx, defaultRole.ID, readerID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.GrantPermission(ctx, readerID, "profile:read"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := ctrl.Register(ctx, model.UserRegister{Email: "user@example.com", Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.ContainsFunc(resp.Meta.Roles, func(r model.RoleDto) bool { return r.Name == "reader" }) {
+		t.Errorf("roles %v: missing inherited role", resp.Meta.Roles)
+	}
+	if !slices.Contains(resp.Meta.Permissions, "profile:read") {
+		t.Errorf("permissions %v: missing inherited permission", resp.Meta.Permissions)
+	}
+
+	login, err := ctrl.Login(ctx, model.UserLogin{Email: "user@example.com", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(login.Meta.Permissions, resp.Meta.Permissions) || len(login.Meta.Roles) != len(resp.Meta.Roles) {
+		t.Errorf("register meta %+v differs from login meta %+v", resp.Meta, login.Meta)
+	}
+}
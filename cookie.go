@@ -0,0 +1,175 @@
+package authgo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+const (
+	defaultTokenCookieName string = "authgo_token"
+	defaultCSRFCookieName  string = "authgo_csrf"
+	defaultCSRFHeaderName  string = "X-CSRF-Token"
+)
+
+var (
+	ErrCSRFTokenMismatch = errors.New("csrf token is missing or invalid")
+	ErrNoCSRFKey         = errors.New("csrf key is required")
+)
+
+// CookieConfig is a config for delivering access token in a cookie instead of response body.
+// Requests authenticated by the cookie are protected from CSRF with a token bound to the access token:
+// it is an HMAC of the session id (or token id without sessions) under CSRFKey, and state-changing
+// requests must send it in CSRF header. The token is delivered in a cookie readable by scripts.
+type CookieConfig struct {
+	// Name is a name of HttpOnly cookie with access token ("authgo_token" by default).
+	// Use "__Host-" prefix to prevent the cookie from being set by subdomains.
+	Name   string `yaml:"name"`
+	Domain string `yaml:"domain"`
+	// Path is a cookie path ("/" by default).
+	Path string `yaml:"path"`
+	// MaxAge is a cookie lifetime in seconds, session cookie is used if zero.
+	MaxAge int `yaml:"max_age"`
+	// SameSite is one of "lax" (default), "strict" or "none".
+	SameSite string `yaml:"same_site"`
+	// Insecure allows sending cookies over plain http, intended for local development only.
+	Insecure bool `yaml:"insecure"`
+	// CSRFCookieName is a name of cookie with csrf token readable by scripts ("authgo_csrf" by default).
+	CSRFCookieName string `yaml:"csrf_cookie_name"`
+	// CSRFHeaderName is a name of header with csrf token ("X-CSRF-Token" by default).
+	CSRFHeaderName string `yaml:"csrf_header_name"`
+	// CSRFKey is a server secret used to derive csrf tokens, at least 32 random bytes are recommended.
+	CSRFKey string `yaml:"csrf_key"`
+}
+
+// withDefaults returns config with default values for empty fields.
+func (cfg CookieConfig) withDefaults() CookieConfig {
+	if cfg.Name == "" {
+		cfg.Name = defaultTokenCookieName
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+	if cfg.CSRFCookieName == "" {
+		cfg.CSRFCookieName = defaultCSRFCookieName
+	}
+	if cfg.CSRFHeaderName == "" {
+		cfg.CSRFHeaderName = defaultCSRFHeaderName
+	}
+	return cfg
+}
+
+func (cfg CookieConfig) sameSite() http.SameSite {
+	switch strings.ToLower(cfg.SameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// cookie creates a cookie with common attributes.
+func (cfg CookieConfig) cookie(name, value string, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   cfg.Domain,
+		Path:     cfg.Path,
+		MaxAge:   cfg.MaxAge,
+		Secure:   !cfg.Insecure,
+		HttpOnly: httpOnly,
+		SameSite: cfg.sameSite(),
+	}
+}
+
+// CSRFToken returns csrf token bound to the session of meta, or to the access token if sessions are not used.
+func CSRFToken(cfg CookieConfig, meta model.AuthMeta) (string, error) {
+	if cfg.CSRFKey == "" {
+		return "", ErrNoCSRFKey
+	}
+
+	var subject string
+	switch {
+	case meta.SessionID != "":
+		subject = "sid:" + meta.SessionID
+	case meta.TokenID != "":
+		subject = "jti:" + meta.TokenID
+	default:
+		return "", fmt.Errorf("csrf token: %w", ErrTokenMissingClaim)
+	}
+
+	mac := hmac.New(sha256.New, []byte(cfg.CSRFKey))
+	mac.Write([]byte(subject))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// AuthCookies creates HttpOnly cookie with access token and a cookie with csrf token bound to it.
+// It can be used with frameworks that don't use http.ResponseWriter, otherwise see SetAuthCookies.
+func AuthCookies(cfg CookieConfig, resp model.AuthResp) (token, csrf *http.Cookie, err error) {
+	cfg = cfg.withDefaults()
+
+	csrfToken, err := CSRFToken(cfg, resp.Meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg.cookie(cfg.Name, resp.Token, true), cfg.cookie(cfg.CSRFCookieName, csrfToken, false), nil
+}
+
+// SetAuthCookies writes cookies with access token from Login, Register or Refresh response and a csrf token.
+func SetAuthCookies(w http.ResponseWriter, cfg CookieConfig, resp model.AuthResp) error {
+	token, csrf, err := AuthCookies(cfg, resp)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, token)
+	http.SetCookie(w, csrf)
+	return nil
+}
+
+// ClearAuthCookies removes cookies with access token and csrf token, e.g. on logout.
+func ClearAuthCookies(w http.ResponseWriter, cfg CookieConfig) {
+	cfg = cfg.withDefaults()
+	cfg.MaxAge = -1
+
+	http.SetCookie(w, cfg.cookie(cfg.Name, "", true))
+	http.SetCookie(w, cfg.cookie(cfg.CSRFCookieName, "", false))
+}
+
+// isSafeMethod reports whether the http method doesn't change state and is not protected from csrf.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// verifyCSRF checks that csrf token from header is bound to the authenticated access token.
+func (cfg CookieConfig) verifyCSRF(r Request, meta model.AuthMeta) error {
+	if isSafeMethod(r.Method()) {
+		return nil
+	}
+
+	expected, err := CSRFToken(cfg, meta)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCSRFTokenMismatch, err)
+	}
+
+	header := r.Header(cfg.CSRFHeaderName)
+	if header == "" || !hmac.Equal([]byte(header), []byte(expected)) {
+		return ErrCSRFTokenMismatch
+	}
+
+	return nil
+}
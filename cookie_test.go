@@ -0,0 +1,79 @@
+package authgo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestVerifyCSRF(t *testing.T) {
+	cfg := CookieConfig{CSRFKey: "cookie-test-csrf-key"}.withDefaults()
+
+	session := model.AuthMeta{UserID: 1, SessionID: "session-a", TokenID: "token-a"}
+	mint := func(meta model.AuthMeta) string {
+		token, err := CSRFToken(cfg, meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		meta   model.AuthMeta
+		want   error
+	}{
+		{name: "own session", method: http.MethodPost, header: mint(session), meta: session},
+		{name: "safe method", method: http.MethodGet, meta: session},
+		{name: "missing header", method: http.MethodPost, meta: session, want: ErrCSRFTokenMismatch},
+		{
+			name:   "another session",
+			method: http.MethodPost,
+			header: mint(model.AuthMeta{UserID: 1, SessionID: "session-b", TokenID: "token-a"}),
+			meta:   session,
+			want:   ErrCSRFTokenMismatch,
+		},
+		{
+			name:   "another user",
+			method: http.MethodDelete,
+			header: mint(model.AuthMeta{UserID: 2, SessionID: "session-c"}),
+			meta:   session,
+			want:   ErrCSRFTokenMismatch,
+		},
+		{
+			name:   "token id without session",
+			method: http.MethodPost,
+			header: mint(model.AuthMeta{UserID: 1, TokenID: "token-a"}),
+			meta:   model.AuthMeta{UserID: 1, TokenID: "token-a"},
+		},
+		{
+			name:   "another token id",
+			method: http.MethodPost,
+			header: mint(model.AuthMeta{UserID: 1, TokenID: "token-b"}),
+			meta:   model.AuthMeta{UserID: 1, TokenID: "token-a"},
+			want:   ErrCSRFTokenMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(cfg.CSRFHeaderName, tt.header)
+			}
+
+			err := cfg.verifyCSRF(NewHTTPRequest(r), tt.meta)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := CSRFToken(CookieConfig{}, session); !errors.Is(err, ErrNoCSRFKey) {
+		t.Errorf("without key: got %v, want ErrNoCSRFKey", err)
+	}
+}
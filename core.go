@@ -12,6 +12,12 @@ type AuthController interface {
 	Login(ctx context.Context, req model.UserLogin) (model.AuthResp, error)
 	// Register executes user register operation.
 	Register(ctx context.Context, req model.UserRegister) (model.AuthResp, error)
+	// Refresh exchanges a refresh token for a new pair of tokens.
+	Refresh(ctx context.Context, refreshToken string) (model.AuthResp, error)
+	// Logout revokes the access token, ends its session and revokes the refresh token family if provided.
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	// RevokeToken revokes the access token before its expiration.
+	RevokeToken(ctx context.Context, accessToken string) error
 }
 
 // UserController provides methods for manipulating with user data.
@@ -32,14 +38,118 @@ type RoleController interface {
 	SetRole(ctx context.Context, userID, roleID int64) error
 	// RemoveRole removes role from user.
 	RemoveRole(ctx context.Context, userID, roleID int64) error
+	// SetRoleUntil assigns role to user until expiration, tokens expire no later than the grant.
+	// A permanent grant of the role is never replaced, ErrPermanentGrant is returned instead.
+	SetRoleUntil(ctx context.Context, grant model.RoleGrant) error
 	// ListRoles returns list of all existing roles.
 	ListRoles(ctx context.Context) ([]model.RoleDto, error)
+	// CreateRole creates a new role.
+	CreateRole(ctx context.Context, req model.RoleCreate) (int64, error)
+	// RenameRole changes name of role, system roles cannot be renamed.
+	RenameRole(ctx context.Context, roleID int64, name string) error
+	// DeleteRole deletes role handling its assignments with strategy, system roles cannot be deleted.
+	DeleteRole(ctx context.Context, roleID int64, strategy RoleDeleteStrategy) error
+	// GrantPermission grants permission to role, wildcard "*" segment grants all permissions in its place.
+	GrantPermission(ctx context.Context, roleID int64, permission string) error
+	// RevokePermission revokes permission from role.
+	RevokePermission(ctx context.Context, roleID int64, permission string) error
+	// ListRolePermissions returns list of permissions granted to role.
+	ListRolePermissions(ctx context.Context, roleID int64) ([]model.PermissionDto, error)
+	// AddInheritedRole makes holders of role also hold inherited role, cycles are rejected with ErrRoleCycle.
+	AddInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error
+	// RemoveInheritedRole removes inheritance between roles.
+	RemoveInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error
+	// ListInheritedRoles returns list of roles inherited by role directly or transitively.
+	ListInheritedRoles(ctx context.Context, roleID int64) ([]model.RoleDto, error)
+}
+
+// OrgController provides methods for manipulating with organizations, their members and org-scoped roles.
+type OrgController interface {
+	// CreateOrg creates a new organization.
+	CreateOrg(ctx context.Context, req model.OrgCreate) (int64, error)
+	// DeleteOrg deletes organization with its memberships.
+	DeleteOrg(ctx context.Context, orgID int64) error
+	// ListUserOrgs returns list of organizations the user is a member of.
+	ListUserOrgs(ctx context.Context, userID int64) ([]model.OrganizationDto, error)
+	// AddMember adds user to organization.
+	AddMember(ctx context.Context, orgID, userID int64) error
+	// RemoveMember removes user from organization with all of the user org roles.
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	// SetOrgRole assigns role to user within organization.
+	SetOrgRole(ctx context.Context, orgID, userID, roleID int64) error
+	// RemoveOrgRole removes role from user within organization.
+	RemoveOrgRole(ctx context.Context, orgID, userID, roleID int64) error
+	// ListOrgUserRoles returns list of roles of user within organization.
+	ListOrgUserRoles(ctx context.Context, orgID, userID int64) ([]model.RoleDto, error)
+	// SwitchOrg reissues tokens of the authenticated user with another active organization,
+	// refreshToken of the user is rotated if refresh tokens are enabled.
+	SwitchOrg(ctx context.Context, meta model.AuthMeta, orgID int64, refreshToken string) (model.AuthResp, error)
+}
+
+// InviteController provides methods for inviting users by email.
+type InviteController interface {
+	// Invite creates an invite with roles and returns its single-use token.
+	Invite(ctx context.Context, req model.InviteCreate) (string, error)
+	// AcceptInvite registers a new user or attaches invite roles to the existing one and returns tokens.
+	AcceptInvite(ctx context.Context, req model.InviteAccept) (model.AuthResp, error)
+	// RevokeInvite revokes a pending invite.
+	RevokeInvite(ctx context.Context, inviteID int64) error
+	// ListPendingInvites returns invites into organization that are not accepted, revoked or expired.
+	ListPendingInvites(ctx context.Context, orgID int64) ([]model.InviteDto, error)
+}
+
+// AccessController provides methods for just-in-time access requests.
+type AccessController interface {
+	// RequestAccess creates a pending request of user for role for a limited time.
+	RequestAccess(ctx context.Context, req model.AccessRequestCreate) (int64, error)
+	// ApproveAccess approves request and grants role to requester until the requested duration passes.
+	ApproveAccess(ctx context.Context, approver model.AuthMeta, requestID int64, comment string) error
+	// DenyAccess denies request.
+	DenyAccess(ctx context.Context, approver model.AuthMeta, requestID int64, comment string) error
+	// CancelAccess cancels pending request on behalf of requester.
+	CancelAccess(ctx context.Context, userID, requestID int64) error
+	// ExpireAccessRequests marks pending requests past their deadline as expired.
+	ExpireAccessRequests(ctx context.Context) (int, error)
+	// ListPendingAccessRequests returns list of requests awaiting decision.
+	ListPendingAccessRequests(ctx context.Context) ([]model.AccessRequestDto, error)
+	// ListUserAccessRequests returns list of requests of user.
+	ListUserAccessRequests(ctx context.Context, userID int64) ([]model.AccessRequestDto, error)
+	// ListAccessRequestEvents returns audit log of request.
+	ListAccessRequestEvents(ctx context.Context, requestID int64) ([]model.AccessRequestEventDto, error)
+}
+
+// SessionController provides methods for manipulating with user auth sessions.
+type SessionController interface {
+	// ListSessions returns list of active sessions of the user.
+	ListSessions(ctx context.Context, userID int64) ([]model.SessionDto, error)
+	// EndSession ends the session, so tokens issued for it are no longer accepted.
+	EndSession(ctx context.Context, sessionID string) error
+	// EndAllSessions ends every active session of the user.
+	EndAllSessions(ctx context.Context, userID int64) error
 }
 
 // Middleware provides methods that can be used during requests to authenticate users and validate access.
 type Middleware interface {
 	// RequireAuth requires to pass access token with every request.
 	RequireAuth(authHeader string) (model.AuthMeta, error)
+	// RequireAuthContext is RequireAuth with request context passed to revocation and session stores.
+	RequireAuthContext(ctx context.Context, authHeader string) (model.AuthMeta, error)
 	// RequireRole requires to have certain role to get access to the resource.
 	RequireRole(meta model.AuthMeta, requiredRole string) error
+	// RequireAnyRole requires to have at least one of the roles.
+	RequireAnyRole(meta model.AuthMeta, roles ...string) error
+	// RequireAllRoles requires to have every role.
+	RequireAllRoles(meta model.AuthMeta, roles ...string) error
+	// RequireRoleExpr requires roles to satisfy the compiled expression, see CompileRoleExpr.
+	RequireRoleExpr(meta model.AuthMeta, expr *RoleExpr) error
+	// RequirePermission requires to have the permission granted by any role, e.g. "invoices:write".
+	RequirePermission(meta model.AuthMeta, permission string) error
+	// RequireOrgRole requires the token to be scoped to the organization and to have the org role in it.
+	// Global roles are not considered, as org roles are not considered by RequireRole.
+	RequireOrgRole(meta model.AuthMeta, orgID int64, requiredRole string) error
+	// RequireOrgPermission requires the token to be scoped to the organization and to have the permission
+	// granted by org roles in it.
+	RequireOrgPermission(meta model.AuthMeta, orgID int64, permission string) error
+	// Authorize requires the policy to allow the action on the resource with given attributes, see WithPolicy.
+	Authorize(meta model.AuthMeta, action string, resource map[string]any) error
 }
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.invite (
+	id bigserial primary key,
+	org_id bigint not null default 0,
+	email text not null,
+	role_ids bigint[] not null default '{}',
+	token_hash text unique not null,
+	invited_by bigint not null,
+	created_at timestamptz not null default current_timestamp,
+	expires_at timestamptz not null,
+	accepted_at timestamptz,
+	revoked_at timestamptz
+);
+create index invite_token_hash on authgo.invite using hash(token_hash);
+create index invite_org_id on authgo.invite(org_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.invite;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- role names become unique, duplicates must be renamed or merged by hand as they may be assigned to users
+do $$
+declare
+	duplicates text;
+begin
+	select string_agg(quote_literal(name), ', ' order by name) into duplicates
+	from (
+		select name from authgo.role
+		group by name
+		having count(*) > 1
+	) d;
+
+	if duplicates is not null then
+		raise exception 'authgo: role names must be unique, rename or merge duplicate roles %', duplicates;
+	end if;
+end
+$$;
+alter table authgo.role
+	add column display_name text not null default '',
+	add column description text not null default '',
+	add column is_system bool not null default false;
+update authgo.role set is_system=true where name='default';
+create unique index role_name_unique on authgo.role(name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index authgo.role_name_unique;
+alter table authgo.role
+	drop column display_name,
+	drop column description,
+	drop column is_system;
+-- +goose StatementEnd
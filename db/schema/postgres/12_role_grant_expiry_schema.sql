@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table authgo.user_role
+	add column expires_at timestamptz,
+	add column granted_by bigint not null default 0,
+	add column reason text not null default '';
+-- a role is granted to a user at most once, existing duplicates are permanent grants of the same role
+delete from authgo.user_role a
+using authgo.user_role b
+where a.user_id=b.user_id and a.role_id=b.role_id and a.ctid > b.ctid;
+create unique index user_role_unique on authgo.user_role(user_id, role_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index authgo.user_role_unique;
+alter table authgo.user_role
+	drop column expires_at,
+	drop column granted_by,
+	drop column reason;
+-- +goose StatementEnd
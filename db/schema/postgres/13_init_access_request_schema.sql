@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- user and role are not foreign keys, so requests and their events outlive deleted users and roles,
+-- role_name keeps the requested role readable in the audit trail
+create table authgo.access_request (
+	id bigserial primary key,
+	user_id bigint not null,
+	role_id bigint not null,
+	role_name text not null default '',
+	reason text not null default '',
+	duration bigint not null,
+	status text not null default 'pending',
+	created_at timestamptz not null default current_timestamp,
+	expires_at timestamptz not null,
+	decided_by bigint not null default 0,
+	decided_at timestamptz
+);
+create index access_request_user_id on authgo.access_request(user_id);
+create index access_request_role_id on authgo.access_request(role_id);
+create index access_request_status on authgo.access_request(status);
+
+create table authgo.access_request_event (
+	id bigserial primary key,
+	request_id bigint not null references authgo.access_request(id) on delete restrict,
+	actor_id bigint not null default 0,
+	status text not null,
+	comment text not null default '',
+	created_at timestamptz not null default current_timestamp
+);
+create index access_request_event_request_id on authgo.access_request_event(request_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.access_request_event;
+drop table authgo.access_request;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.refresh_token (
+	id bigserial primary key,
+	user_id bigint not null,
+	family_id text not null,
+	token_hash text unique not null,
+	created_at timestamptz not null default current_timestamp,
+	expires_at timestamptz not null,
+	used_at timestamptz,
+	revoked_at timestamptz
+);
+create index refresh_token_hash on authgo.refresh_token using hash(token_hash);
+create index refresh_token_family on authgo.refresh_token using hash(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.refresh_token;
+-- +goose StatementEnd
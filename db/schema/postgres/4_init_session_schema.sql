@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.session (
+	id text primary key,
+	user_id bigint not null,
+	user_agent text not null default '',
+	ip text not null default '',
+	created_at timestamptz not null default current_timestamp,
+	last_seen_at timestamptz not null default current_timestamp,
+	expires_at timestamptz not null,
+	ended_at timestamptz
+);
+create index session_user_id on authgo.session using hash(user_id);
+
+alter table authgo.refresh_token add column session_id text not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table authgo.refresh_token drop column session_id;
+
+drop table authgo.session;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.revoked_token (
+	jti text primary key,
+	expires_at timestamptz not null,
+	revoked_at timestamptz not null default current_timestamp
+);
+create index revoked_token_expires_at on authgo.revoked_token(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.revoked_token;
+-- +goose StatementEnd
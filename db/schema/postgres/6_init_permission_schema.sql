@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.permission (
+	id bigserial primary key,
+	name text unique not null,
+	description text not null default '',
+	created_at timestamp not null default current_timestamp
+);
+
+create table authgo.role_permission (
+	role_id bigint not null references authgo.role(id) on delete cascade,
+	permission_id bigint not null references authgo.permission(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (role_id, permission_id)
+);
+create index role_permission_permission_id on authgo.role_permission(permission_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.role_permission;
+drop table authgo.permission;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.role_inheritance (
+	role_id bigint not null references authgo.role(id) on delete cascade,
+	inherited_role_id bigint not null references authgo.role(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (role_id, inherited_role_id),
+	check (role_id <> inherited_role_id)
+);
+create index role_inheritance_inherited_role_id on authgo.role_inheritance(inherited_role_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.role_inheritance;
+-- +goose StatementEnd
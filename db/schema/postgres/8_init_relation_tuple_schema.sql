@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.relation_tuple_revision (
+	revision bigserial primary key,
+	created_at timestamp not null default current_timestamp
+);
+
+create table authgo.relation_tuple (
+	namespace text not null,
+	object_id text not null,
+	relation text not null,
+	subject_namespace text not null,
+	subject_id text not null,
+	subject_relation text not null default '',
+	created_revision bigint not null,
+	deleted_revision bigint
+);
+create unique index relation_tuple_live on authgo.relation_tuple(
+	namespace, object_id, relation, subject_namespace, subject_id, subject_relation
+) where deleted_revision is null;
+create index relation_tuple_object on authgo.relation_tuple(namespace, object_id, relation);
+create index relation_tuple_subject on authgo.relation_tuple(subject_namespace, subject_id, subject_relation);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table authgo.relation_tuple;
+drop table authgo.relation_tuple_revision;
+-- +goose StatementEnd
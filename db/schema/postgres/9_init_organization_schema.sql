@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+create table authgo.organization (
+	id bigserial primary key,
+	name text not null,
+	slug text unique not null,
+	created_at timestamp not null default current_timestamp
+);
+
+create table authgo.membership (
+	org_id bigint not null references authgo.organization(id) on delete cascade,
+	user_id bigint not null references authgo.user(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (org_id, user_id)
+);
+create index membership_user_id on authgo.membership(user_id);
+
+create table authgo.org_user_role (
+	org_id bigint not null,
+	user_id bigint not null,
+	role_id bigint not null references authgo.role(id) on delete cascade,
+	created_at timestamp not null default current_timestamp,
+	primary key (org_id, user_id, role_id),
+	foreign key (org_id, user_id) references authgo.membership(org_id, user_id) on delete cascade
+);
+
+alter table authgo.refresh_token add column org_id bigint not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table authgo.refresh_token drop column org_id;
+
+drop table authgo.org_user_role;
+drop table authgo.membership;
+drop table authgo.organization;
+-- +goose StatementEnd
@@ -0,0 +1,127 @@
+// Package echoauth provides echo middlewares that authenticate requests with authgo.
+package echoauth
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+)
+
+// ErrorHandler returns an error for a request rejected with the status, which is handled by echo.HTTPErrorHandler.
+type ErrorHandler func(c echo.Context, status int, err error) error
+
+// Option configures middlewares.
+type Option func(cfg *config)
+
+type config struct {
+	onError ErrorHandler
+	guard   []authgo.GuardOption
+}
+
+// WithErrorHandler sets a function that handles unauthenticated and forbidden requests.
+// By default an echo.HTTPError with authgo.ErrorMessage(err) is returned, the error itself is kept as internal.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(cfg *config) {
+		cfg.onError = h
+	}
+}
+
+// WithGuardOptions configures authgo.Guard used by middlewares, e.g. enables authgo.WithCookieTransport.
+func WithGuardOptions(opts ...authgo.GuardOption) Option {
+	return func(cfg *config) {
+		cfg.guard = append(cfg.guard, opts...)
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		onError: func(_ echo.Context, status int, err error) error {
+			return echo.NewHTTPError(status, authgo.ErrorMessage(err)).SetInternal(err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Authenticate requires a valid access token and puts model.AuthMeta into request context,
+// which can be read with MetaFromContext.
+func Authenticate(m authgo.Middleware, opts ...Option) echo.MiddlewareFunc {
+	cfg := newConfig(opts)
+	guard := authgo.NewGuard(m, cfg.guard...)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			meta, err := guard.Authenticate(authgo.NewHTTPRequest(c.Request()))
+			if err != nil {
+				return cfg.onError(c, authgo.StatusCode(err), err)
+			}
+
+			c.SetRequest(c.Request().WithContext(authgo.WithMeta(c.Request().Context(), meta)))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole requires the user to have the role, must be used after Authenticate.
+func RequireRole(m authgo.Middleware, role string, opts ...Option) echo.MiddlewareFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRole(meta, role)
+	})
+}
+
+// RequireAnyRole requires the user to have at least one of the roles, must be used after Authenticate.
+func RequireAnyRole(m authgo.Middleware, roles []string, opts ...Option) echo.MiddlewareFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAnyRole(meta, roles...)
+	})
+}
+
+// RequireAllRoles requires the user to have every role, must be used after Authenticate.
+func RequireAllRoles(m authgo.Middleware, roles []string, opts ...Option) echo.MiddlewareFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAllRoles(meta, roles...)
+	})
+}
+
+// RequireRoleExpr requires roles of the user to satisfy the expression, must be used after Authenticate.
+func RequireRoleExpr(m authgo.Middleware, expr *authgo.RoleExpr, opts ...Option) echo.MiddlewareFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRoleExpr(meta, expr)
+	})
+}
+
+// RequirePermission requires the user to have the permission, must be used after Authenticate.
+func RequirePermission(m authgo.Middleware, permission string, opts ...Option) echo.MiddlewareFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequirePermission(meta, permission)
+	})
+}
+
+// MetaFromContext returns model.AuthMeta put into request context by Authenticate.
+func MetaFromContext(c echo.Context) (model.AuthMeta, bool) {
+	return authgo.MetaFromContext(c.Request().Context())
+}
+
+func require(cfg config, check func(meta model.AuthMeta) error) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			meta, ok := MetaFromContext(c)
+			if !ok {
+				return cfg.onError(c, authgo.StatusCode(authgo.ErrMissingJwt), authgo.ErrMissingJwt)
+			}
+
+			if err := check(meta); err != nil {
+				return cfg.onError(c, authgo.StatusCode(err), err)
+			}
+
+			return next(c)
+		}
+	}
+}
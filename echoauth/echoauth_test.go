@@ -0,0 +1,28 @@
+package echoauth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/guardtest"
+)
+
+func TestConformance(t *testing.T) {
+	guardtest.Run(t, func(t *testing.T, m authgo.Middleware, opts ...authgo.GuardOption) http.Handler {
+		handler := func(c echo.Context) error {
+			meta, _ := MetaFromContext(c)
+			guardtest.WriteUserID(c.Response(), meta)
+			return nil
+		}
+
+		e := echo.New()
+		g := e.Group("", Authenticate(m, WithGuardOptions(opts...)))
+		methods := []string{http.MethodGet, http.MethodPost}
+		g.Match(methods, guardtest.PathAuth, handler)
+		g.Match(methods, guardtest.PathRole, handler, RequireRole(m, guardtest.Role))
+		g.Match(methods, guardtest.PathPermission, handler, RequirePermission(m, guardtest.Permission))
+		return e
+	})
+}
@@ -0,0 +1,147 @@
+// Package fiberauth provides fiber middlewares that authenticate requests with authgo.
+package fiberauth
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+)
+
+// ErrorHandler handles a request rejected with the status.
+type ErrorHandler func(c *fiber.Ctx, status int, err error) error
+
+// Option configures middlewares.
+type Option func(cfg *config)
+
+type config struct {
+	onError ErrorHandler
+	guard   []authgo.GuardOption
+}
+
+// WithErrorHandler sets a function that handles unauthenticated and forbidden requests.
+// By default the status is written with JSON body {"error": authgo.ErrorMessage(err)}.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(cfg *config) {
+		cfg.onError = h
+	}
+}
+
+// WithGuardOptions configures authgo.Guard used by middlewares, e.g. enables authgo.WithCookieTransport.
+func WithGuardOptions(opts ...authgo.GuardOption) Option {
+	return func(cfg *config) {
+		cfg.guard = append(cfg.guard, opts...)
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		onError: func(c *fiber.Ctx, status int, err error) error {
+			return c.Status(status).JSON(fiber.Map{"error": authgo.ErrorMessage(err)})
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Authenticate requires a valid access token and puts model.AuthMeta into user context,
+// which can be read with MetaFromContext.
+func Authenticate(m authgo.Middleware, opts ...Option) fiber.Handler {
+	cfg := newConfig(opts)
+	guard := authgo.NewGuard(m, cfg.guard...)
+
+	return func(c *fiber.Ctx) error {
+		meta, err := guard.Authenticate(request{c: c})
+		if err != nil {
+			return cfg.onError(c, authgo.StatusCode(err), err)
+		}
+
+		c.SetUserContext(authgo.WithMeta(c.UserContext(), meta))
+		return c.Next()
+	}
+}
+
+// RequireRole requires the user to have the role, must be used after Authenticate.
+func RequireRole(m authgo.Middleware, role string, opts ...Option) fiber.Handler {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRole(meta, role)
+	})
+}
+
+// RequireAnyRole requires the user to have at least one of the roles, must be used after Authenticate.
+func RequireAnyRole(m authgo.Middleware, roles []string, opts ...Option) fiber.Handler {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAnyRole(meta, roles...)
+	})
+}
+
+// RequireAllRoles requires the user to have every role, must be used after Authenticate.
+func RequireAllRoles(m authgo.Middleware, roles []string, opts ...Option) fiber.Handler {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAllRoles(meta, roles...)
+	})
+}
+
+// RequireRoleExpr requires roles of the user to satisfy the expression, must be used after Authenticate.
+func RequireRoleExpr(m authgo.Middleware, expr *authgo.RoleExpr, opts ...Option) fiber.Handler {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRoleExpr(meta, expr)
+	})
+}
+
+// RequirePermission requires the user to have the permission, must be used after Authenticate.
+func RequirePermission(m authgo.Middleware, permission string, opts ...Option) fiber.Handler {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequirePermission(meta, permission)
+	})
+}
+
+// MetaFromContext returns model.AuthMeta put into user context by Authenticate.
+func MetaFromContext(c *fiber.Ctx) (model.AuthMeta, bool) {
+	return authgo.MetaFromContext(c.UserContext())
+}
+
+func require(cfg config, check func(meta model.AuthMeta) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		meta, ok := MetaFromContext(c)
+		if !ok {
+			return cfg.onError(c, authgo.StatusCode(authgo.ErrMissingJwt), authgo.ErrMissingJwt)
+		}
+
+		if err := check(meta); err != nil {
+			return cfg.onError(c, authgo.StatusCode(err), err)
+		}
+
+		return c.Next()
+	}
+}
+
+// request adapts *fiber.Ctx to authgo.Request.
+type request struct {
+	c *fiber.Ctx
+}
+
+func (r request) Context() context.Context {
+	return r.c.UserContext()
+}
+
+func (r request) Header(name string) string {
+	return r.c.Get(name)
+}
+
+func (r request) Cookie(name string) (string, bool) {
+	value := r.c.Cookies(name)
+	return value, value != ""
+}
+
+func (r request) Method() string {
+	return r.c.Method()
+}
@@ -0,0 +1,30 @@
+package fiberauth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/guardtest"
+)
+
+func TestConformance(t *testing.T) {
+	guardtest.Run(t, func(t *testing.T, m authgo.Middleware, opts ...authgo.GuardOption) http.Handler {
+		handler := func(c *fiber.Ctx) error {
+			meta, _ := MetaFromContext(c)
+			guardtest.WriteUserID(c, meta)
+			return nil
+		}
+
+		app := fiber.New()
+		g := app.Group("/", Authenticate(m, WithGuardOptions(opts...)))
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			g.Add(method, guardtest.PathAuth, handler)
+			g.Add(method, guardtest.PathRole, RequireRole(m, guardtest.Role), handler)
+			g.Add(method, guardtest.PathPermission, RequirePermission(m, guardtest.Permission), handler)
+		}
+		return adaptor.FiberApp(app)
+	})
+}
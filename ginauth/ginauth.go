@@ -0,0 +1,126 @@
+// Package ginauth provides gin middlewares that authenticate requests with authgo.
+package ginauth
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+)
+
+// ErrorHandler writes a response for a request rejected with the status and aborts the chain.
+type ErrorHandler func(c *gin.Context, status int, err error)
+
+// Option configures middlewares.
+type Option func(cfg *config)
+
+type config struct {
+	onError ErrorHandler
+	guard   []authgo.GuardOption
+}
+
+// WithErrorHandler sets a function that writes responses for unauthenticated and forbidden requests.
+// By default the status is written with JSON body {"error": authgo.ErrorMessage(err)}.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(cfg *config) {
+		cfg.onError = h
+	}
+}
+
+// WithGuardOptions configures authgo.Guard used by middlewares, e.g. enables authgo.WithCookieTransport.
+func WithGuardOptions(opts ...authgo.GuardOption) Option {
+	return func(cfg *config) {
+		cfg.guard = append(cfg.guard, opts...)
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		onError: func(c *gin.Context, status int, err error) {
+			c.AbortWithStatusJSON(status, gin.H{"error": authgo.ErrorMessage(err)})
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Authenticate requires a valid access token and puts model.AuthMeta into request context,
+// which can be read with MetaFromContext.
+func Authenticate(m authgo.Middleware, opts ...Option) gin.HandlerFunc {
+	cfg := newConfig(opts)
+	guard := authgo.NewGuard(m, cfg.guard...)
+
+	return func(c *gin.Context) {
+		meta, err := guard.Authenticate(authgo.NewHTTPRequest(c.Request))
+		if err != nil {
+			cfg.onError(c, authgo.StatusCode(err), err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(authgo.WithMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
+
+// RequireRole requires the user to have the role, must be used after Authenticate.
+func RequireRole(m authgo.Middleware, role string, opts ...Option) gin.HandlerFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRole(meta, role)
+	})
+}
+
+// RequireAnyRole requires the user to have at least one of the roles, must be used after Authenticate.
+func RequireAnyRole(m authgo.Middleware, roles []string, opts ...Option) gin.HandlerFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAnyRole(meta, roles...)
+	})
+}
+
+// RequireAllRoles requires the user to have every role, must be used after Authenticate.
+func RequireAllRoles(m authgo.Middleware, roles []string, opts ...Option) gin.HandlerFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAllRoles(meta, roles...)
+	})
+}
+
+// RequireRoleExpr requires roles of the user to satisfy the expression, must be used after Authenticate.
+func RequireRoleExpr(m authgo.Middleware, expr *authgo.RoleExpr, opts ...Option) gin.HandlerFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRoleExpr(meta, expr)
+	})
+}
+
+// RequirePermission requires the user to have the permission, must be used after Authenticate.
+func RequirePermission(m authgo.Middleware, permission string, opts ...Option) gin.HandlerFunc {
+	guard := authgo.NewGuard(m)
+	return require(newConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequirePermission(meta, permission)
+	})
+}
+
+// MetaFromContext returns model.AuthMeta put into request context by Authenticate.
+func MetaFromContext(c *gin.Context) (model.AuthMeta, bool) {
+	return authgo.MetaFromContext(c.Request.Context())
+}
+
+func require(cfg config, check func(meta model.AuthMeta) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meta, ok := MetaFromContext(c)
+		if !ok {
+			cfg.onError(c, authgo.StatusCode(authgo.ErrMissingJwt), authgo.ErrMissingJwt)
+			return
+		}
+
+		if err := check(meta); err != nil {
+			cfg.onError(c, authgo.StatusCode(err), err)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package ginauth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/guardtest"
+)
+
+func TestConformance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	guardtest.Run(t, func(t *testing.T, m authgo.Middleware, opts ...authgo.GuardOption) http.Handler {
+		handler := func(c *gin.Context) {
+			meta, _ := MetaFromContext(c)
+			guardtest.WriteUserID(c.Writer, meta)
+		}
+
+		r := gin.New()
+		g := r.Group("/", Authenticate(m, WithGuardOptions(opts...)))
+		g.Match([]string{http.MethodGet, http.MethodPost}, guardtest.PathAuth, handler)
+		g.Match([]string{http.MethodGet, http.MethodPost}, guardtest.PathRole, RequireRole(m, guardtest.Role), handler)
+		g.Match([]string{http.MethodGet, http.MethodPost}, guardtest.PathPermission, RequirePermission(m, guardtest.Permission), handler)
+		return r
+	})
+}
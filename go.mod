@@ -3,6 +3,7 @@ module github.com/yogenyslav/authgo
 go 1.24.2
 
 require (
+	github.com/go-jose/go-jose/v4 v4.1.5
 	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/jackc/pgx/v5 v5.7.4
 	github.com/pressly/goose/v3 v3.24.2
@@ -0,0 +1,52 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrInvalidGrant   = errors.New("role grant must expire in the future")
+	ErrPermanentGrant = errors.New("user holds the role permanently")
+)
+
+// SetRoleUntil assigns role to user until expiration. Access tokens issued while the grant is active
+// expire no later than the grant, and the role is not included into tokens issued after it.
+// ErrPermanentGrant is returned if the user already holds the role permanently, the permanent grant is kept.
+func (ctrl *controller) SetRoleUntil(ctx context.Context, grant model.RoleGrant) error {
+	if !grant.ExpiresAt.After(time.Now()) {
+		return ErrInvalidGrant
+	}
+
+	roles, err := ctrl.role.ListUserRoles(ctx, grant.UserID)
+	if err != nil {
+		return fmt.Errorf("list user roles: %w", err)
+	}
+	for _, role := range roles {
+		if role.ID == grant.RoleID && role.ExpiresAt == nil {
+			return ErrPermanentGrant
+		}
+	}
+
+	if err := ctrl.user.SetRoleUntil(ctx, grant); err != nil {
+		return fmt.Errorf("set role until: %w", err)
+	}
+	return nil
+}
+
+// rolesExpireAt returns the earliest expiration of time-bound roles, ok is false if all roles are permanent.
+func rolesExpireAt(roles []model.RoleDto) (expiresAt time.Time, ok bool) {
+	for _, role := range roles {
+		if role.ExpiresAt == nil {
+			continue
+		}
+		if !ok || role.ExpiresAt.Before(expiresAt) {
+			expiresAt, ok = *role.ExpiresAt, true
+		}
+	}
+	return expiresAt, ok
+}
@@ -0,0 +1,44 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestSetRoleUntil(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := newTestController(t, AuthConfig{})
+	user := registerUser(t, ctrl, "user")
+	roleID := createRoles(t, ctrl, "admin")["admin"]
+
+	grant := model.RoleGrant{UserID: user.Meta.UserID, RoleID: roleID, ExpiresAt: time.Now().Add(-time.Second)}
+	if err := ctrl.SetRoleUntil(ctx, grant); !errors.Is(err, ErrInvalidGrant) {
+		t.Fatalf("past expiry: got %v, want ErrInvalidGrant", err)
+	}
+
+	grant.ExpiresAt = time.Now().Add(time.Hour)
+	if err := ctrl.SetRoleUntil(ctx, grant); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.SetRole(ctx, user.Meta.UserID, roleID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.SetRoleUntil(ctx, grant); !errors.Is(err, ErrPermanentGrant) {
+		t.Fatalf("permanent grant: got %v, want ErrPermanentGrant", err)
+	}
+
+	roles, err := ctrl.role.ListUserRoles(ctx, user.Meta.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range roles {
+		if role.ID == roleID && role.ExpiresAt != nil {
+			t.Errorf("permanent grant expires at %v", role.ExpiresAt)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package grpcauth provides gRPC server interceptors that authenticate requests with authgo.
+package grpcauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// authorizationKey is a metadata key with bearer token.
+	authorizationKey string = "authorization"
+)
+
+// RoleResolver returns roles required to call the method (e.g. "/pkg.Service/Method").
+// Access is granted if the user has any of them, empty list requires only authentication.
+type RoleResolver func(fullMethod string) []string
+
+// Option configures interceptors.
+type Option func(cfg *config)
+
+type config struct {
+	resolvers []RoleResolver
+	public    map[string]struct{}
+}
+
+// WithMethodRoles requires roles per method, the map is keyed by full method name.
+func WithMethodRoles(roles map[string][]string) Option {
+	return func(cfg *config) {
+		cfg.resolvers = append(cfg.resolvers, func(fullMethod string) []string {
+			return roles[fullMethod]
+		})
+	}
+}
+
+// WithRoleResolver requires roles returned by the resolver, e.g. ProtoOptionRoles.
+func WithRoleResolver(r RoleResolver) Option {
+	return func(cfg *config) {
+		cfg.resolvers = append(cfg.resolvers, r)
+	}
+}
+
+// WithPublicMethods skips authentication for the methods, e.g. health checks.
+func WithPublicMethods(fullMethods ...string) Option {
+	return func(cfg *config) {
+		for _, method := range fullMethods {
+			cfg.public[method] = struct{}{}
+		}
+	}
+}
+
+// ProtoOptionRoles creates a RoleResolver that reads required roles from a repeated string
+// method option, e.g.:
+//
+//	extend google.protobuf.MethodOptions {
+//		repeated string required_roles = 50001;
+//	}
+func ProtoOptionRoles(ext protoreflect.ExtensionType) RoleResolver {
+	return func(fullMethod string) []string {
+		name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+		if err != nil {
+			return nil
+		}
+
+		method, ok := desc.(protoreflect.MethodDescriptor)
+		if !ok {
+			return nil
+		}
+
+		opts, ok := method.Options().(*descriptorpb.MethodOptions)
+		if !ok || !proto.HasExtension(opts, ext) {
+			return nil
+		}
+
+		roles, _ := proto.GetExtension(opts, ext).([]string)
+		return roles
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		public: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// UnaryServerInterceptor authenticates unary calls and puts model.AuthMeta into context,
+// which can be read with authgo.MetaFromContext.
+func UnaryServerInterceptor(m authgo.Middleware, opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := cfg.authorize(ctx, m, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming calls and puts model.AuthMeta into stream context,
+// which can be read with authgo.MetaFromContext.
+func StreamServerInterceptor(m authgo.Middleware, opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := cfg.authorize(ss.Context(), m, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize authenticates the call and checks roles required for the method.
+func (cfg *config) authorize(ctx context.Context, m authgo.Middleware, fullMethod string) (context.Context, error) {
+	if _, ok := cfg.public[fullMethod]; ok {
+		return ctx, nil
+	}
+
+	var authHeader string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationKey); len(values) > 0 {
+			authHeader = values[0]
+		}
+	}
+
+	meta, err := m.RequireAuthContext(ctx, authHeader)
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, authgo.ErrorMessage(err))
+	}
+
+	if err := cfg.requireRoles(m, meta, fullMethod); err != nil {
+		return ctx, status.Error(codes.PermissionDenied, authgo.ErrorMessage(err))
+	}
+
+	return authgo.WithMeta(ctx, meta), nil
+}
+
+// requireRoles checks that the user has any of the roles required for the method.
+func (cfg *config) requireRoles(m authgo.Middleware, meta model.AuthMeta, fullMethod string) error {
+	for _, resolve := range cfg.resolvers {
+		roles := resolve(fullMethod)
+		if len(roles) == 0 {
+			continue
+		}
+
+		if err := m.RequireAnyRole(meta, roles...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serverStream overrides context of grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
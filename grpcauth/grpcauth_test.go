@@ -0,0 +1,182 @@
+package grpcauth
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+)
+
+var cfg = authgo.JwtConfig{
+	Secret: "grpcauth-test-secret-grpcauth-test",
+	Expire: 1,
+}
+
+// issueTokens returns tokens of a user without roles and of an admin.
+func issueTokens(t *testing.T) (user, admin string) {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	ctrl, err := authgo.NewAuthController(authgo.AuthConfig{Jwt: cfg}, memory.NewUserStore(db), memory.NewRoleStore(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roleID, err := ctrl.CreateRole(ctx, model.RoleCreate{Name: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userResp, err := ctrl.Register(ctx, model.UserRegister{Email: "user@example.com", Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminResp, err := ctrl.Register(ctx, model.UserRegister{Email: "admin@example.com", Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.SetRole(ctx, adminResp.Meta.UserID, roleID); err != nil {
+		t.Fatal(err)
+	}
+	adminResp, err = ctrl.Login(ctx, model.UserLogin{Email: "admin@example.com", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return userResp.Token, adminResp.Token
+}
+
+// newClient starts a health server with auth interceptors over bufconn, Watch requires the admin role.
+func newClient(t *testing.T) healthpb.HealthClient {
+	t.Helper()
+
+	m := authgo.NewAuthMiddleware(cfg)
+	opts := []Option{WithMethodRoles(map[string][]string{watchMethod: {"admin"}})}
+
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(m, opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(m, opts...)),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func withToken(token string) context.Context {
+	if token == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), authorizationKey, "Bearer "+token)
+}
+
+func checkStatus(t *testing.T, err error, code codes.Code, msg string) {
+	t.Helper()
+
+	st, _ := status.FromError(err)
+	if st.Code() != code {
+		t.Fatalf("code = %s, want %s (%v)", st.Code(), code, err)
+	}
+	if code != codes.OK && st.Message() != msg {
+		t.Fatalf("message = %q, want %q", st.Message(), msg)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user, _ := issueTokens(t)
+	client := newClient(t)
+
+	tests := []struct {
+		name  string
+		token string
+		code  codes.Code
+		msg   string
+	}{
+		{name: "missing token", code: codes.Unauthenticated, msg: authgo.ErrMissingJwt.Error()},
+		{name: "malformed token", token: "not-a-jwt", code: codes.Unauthenticated, msg: authgo.ErrTokenMalformed.Error()},
+		{name: "invalid signature", token: user[:len(user)-4] + "AAAA", code: codes.Unauthenticated, msg: authgo.ErrTokenSignature.Error()},
+		{name: "valid token", token: user, code: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Check(withToken(tt.token), &healthpb.HealthCheckRequest{})
+			checkStatus(t, err, tt.code, tt.msg)
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	user, admin := issueTokens(t)
+	client := newClient(t)
+
+	tests := []struct {
+		name  string
+		token string
+		code  codes.Code
+		msg   string
+	}{
+		{name: "missing token", code: codes.Unauthenticated, msg: authgo.ErrMissingJwt.Error()},
+		{name: "missing role", token: user, code: codes.PermissionDenied, msg: authgo.ErrForbidden.Error()},
+		{name: "valid token", token: admin, code: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(withToken(tt.token))
+			defer cancel()
+
+			stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = stream.Recv()
+			checkStatus(t, err, tt.code, tt.msg)
+		})
+	}
+}
+
+func TestPublicMethods(t *testing.T) {
+	m := authgo.NewAuthMiddleware(cfg)
+
+	cfg := newConfig([]Option{WithPublicMethods(checkMethod)})
+	if _, err := cfg.authorize(context.Background(), m, checkMethod); err != nil {
+		t.Fatalf("public method: %v", err)
+	}
+	if _, err := cfg.authorize(context.Background(), m, watchMethod); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("protected method: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// Request is a framework independent view of an incoming request, implemented by adapters for web frameworks.
+type Request interface {
+	// Context returns context of the request.
+	Context() context.Context
+	// Header returns the first value of the request header.
+	Header(name string) string
+	// Cookie returns the value of the request cookie.
+	Cookie(name string) (string, bool)
+	// Method returns http method of the request.
+	Method() string
+}
+
+// Guard is a core of framework adapters: it authenticates requests and checks roles with Middleware,
+// so every adapter behaves the same and only converts requests and responses.
+type Guard struct {
+	m      Middleware
+	cookie *CookieConfig
+}
+
+// GuardOption configures Guard.
+type GuardOption func(g *Guard)
+
+// WithCookieTransport makes Guard read access token from the cookie when Authorization header is absent.
+// State-changing requests authenticated by the cookie must send csrf token bound to the access token, see CSRFToken.
+func WithCookieTransport(cfg CookieConfig) GuardOption {
+	return func(g *Guard) {
+		cfg = cfg.withDefaults()
+		g.cookie = &cfg
+	}
+}
+
+// NewGuard creates a Guard over the Middleware.
+func NewGuard(m Middleware, opts ...GuardOption) *Guard {
+	g := &Guard{
+		m: m,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Authenticate validates access token of the request and returns its AuthMeta.
+// The token is read from Authorization header or, if cookie transport is enabled, from the cookie.
+func (g *Guard) Authenticate(r Request) (model.AuthMeta, error) {
+	authHeader := r.Header("Authorization")
+	if authHeader != "" || g.cookie == nil {
+		return g.m.RequireAuthContext(r.Context(), authHeader)
+	}
+
+	token, ok := r.Cookie(g.cookie.Name)
+	if !ok || token == "" {
+		return g.m.RequireAuthContext(r.Context(), authHeader)
+	}
+
+	meta, err := g.m.RequireAuthContext(r.Context(), typeBearerToken+" "+token)
+	if err != nil {
+		return meta, err
+	}
+
+	if err := g.cookie.verifyCSRF(r, meta); err != nil {
+		return model.AuthMeta{}, err
+	}
+
+	return meta, nil
+}
+
+// RequireRole checks that the user has the role.
+func (g *Guard) RequireRole(meta model.AuthMeta, role string) error {
+	return g.m.RequireRole(meta, role)
+}
+
+// RequireAnyRole checks that the user has at least one of the roles.
+func (g *Guard) RequireAnyRole(meta model.AuthMeta, roles ...string) error {
+	return g.m.RequireAnyRole(meta, roles...)
+}
+
+// RequireAllRoles checks that the user has every role.
+func (g *Guard) RequireAllRoles(meta model.AuthMeta, roles ...string) error {
+	return g.m.RequireAllRoles(meta, roles...)
+}
+
+// RequireRoleExpr checks that roles of the user satisfy the expression.
+func (g *Guard) RequireRoleExpr(meta model.AuthMeta, expr *RoleExpr) error {
+	return g.m.RequireRoleExpr(meta, expr)
+}
+
+// RequirePermission checks that the user has the permission.
+func (g *Guard) RequirePermission(meta model.AuthMeta, permission string) error {
+	return g.m.RequirePermission(meta, permission)
+}
+
+// RequireOrgRole checks that the token is scoped to the organization and the user has the role in it.
+func (g *Guard) RequireOrgRole(meta model.AuthMeta, orgID int64, role string) error {
+	return g.m.RequireOrgRole(meta, orgID, role)
+}
+
+// RequireOrgPermission checks that the token is scoped to the organization and the user has the permission in it.
+func (g *Guard) RequireOrgPermission(meta model.AuthMeta, orgID int64, permission string) error {
+	return g.m.RequireOrgPermission(meta, orgID, permission)
+}
+
+// Authorize checks that the policy allows the user to perform the action on the resource.
+func (g *Guard) Authorize(meta model.AuthMeta, action string, resource map[string]any) error {
+	return g.m.Authorize(meta, action, resource)
+}
+
+// StatusCode returns http status for errors returned by Guard: 403 for forbidden access and failed csrf check,
+// otherwise 401.
+func StatusCode(err error) int {
+	if errors.Is(err, ErrForbidden) || errors.Is(err, ErrCSRFTokenMismatch) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// publicErrors are errors whose messages are safe to send to clients, details wrapped into them are never exposed.
+var publicErrors = []error{
+	ErrForbidden,
+	ErrCSRFTokenMismatch,
+	ErrMissingJwt,
+	ErrTokenMalformed,
+	ErrTokenSignature,
+	ErrJwtSignMethod,
+	ErrTokenExpired,
+	ErrTokenNotYetValid,
+	ErrTokenIssuedInFuture,
+	ErrTokenInvalidIssuer,
+	ErrTokenInvalidAudience,
+	ErrTokenMissingClaim,
+	ErrTokenRevoked,
+	ErrSessionEnded,
+}
+
+// defaultErrorMessage is sent to clients for errors that are not public, e.g. failures of stores.
+const defaultErrorMessage = "authentication failed"
+
+// ErrorMessage returns a fixed message for errors returned by Guard and Middleware that is safe to send to clients:
+// the message of the matched sentinel error without the wrapped chain, or a generic one.
+func ErrorMessage(err error) string {
+	for _, e := range publicErrors {
+		if errors.Is(err, e) {
+			return e.Error()
+		}
+	}
+	return defaultErrorMessage
+}
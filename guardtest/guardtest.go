@@ -0,0 +1,215 @@
+// Package guardtest provides a conformance suite for framework adapters of authgo,
+// so every adapter is checked to authenticate and authorize requests the same way.
+package guardtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yogenyslav/authgo"
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store/memory"
+)
+
+// Paths that must be served by the handler under test. Each of them responds with 200 and
+// the id of the authenticated user in the body when access is granted.
+const (
+	// PathAuth requires authentication only.
+	PathAuth string = "/auth"
+	// PathRole requires Role after authentication.
+	PathRole string = "/role"
+	// PathPermission requires Permission after authentication.
+	PathPermission string = "/permission"
+)
+
+const (
+	// Role is required by PathRole.
+	Role string = "conformance-admin"
+	// Permission is required by PathPermission.
+	Permission string = "conformance:read"
+)
+
+// Setup creates a handler that serves the paths with adapter middlewares over m,
+// guard options must be passed to the adapter Authenticate middleware.
+type Setup func(t *testing.T, m authgo.Middleware, opts ...authgo.GuardOption) http.Handler
+
+// WriteUserID writes id of the authenticated user, it can be used by handlers of the paths.
+func WriteUserID(w io.Writer, meta model.AuthMeta) {
+	_, _ = io.WriteString(w, strconv.FormatInt(meta.UserID, 10))
+}
+
+var jwtConfig = authgo.JwtConfig{
+	Secret: "guardtest-secret-guardtest-secret",
+	Expire: 1,
+}
+
+// leaks are parts of error chains and requirements that must never be sent to clients.
+var leaks = []string{
+	"parse authorization header",
+	"invalid token:",
+	"validate",
+	"requirement",
+	Role,
+	Permission,
+}
+
+// fixture holds tokens of the users taking part in the suite.
+type fixture struct {
+	user  model.AuthResp
+	admin model.AuthResp
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewDB()
+	ctrl, err := authgo.NewAuthController(authgo.AuthConfig{Jwt: jwtConfig}, memory.NewUserStore(db), memory.NewRoleStore(db))
+	if err != nil {
+		t.Fatalf("auth controller: %v", err)
+	}
+
+	roleID, err := ctrl.CreateRole(ctx, model.RoleCreate{Name: Role})
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if err := ctrl.GrantPermission(ctx, roleID, Permission); err != nil {
+		t.Fatalf("grant permission: %v", err)
+	}
+
+	var f fixture
+	for _, u := range []struct {
+		resp  *model.AuthResp
+		email string
+		admin bool
+	}{
+		{resp: &f.user, email: "user@example.com"},
+		{resp: &f.admin, email: "admin@example.com", admin: true},
+	} {
+		resp, err := ctrl.Register(ctx, model.UserRegister{Email: u.email, Username: u.email, Password: "password"})
+		if err != nil {
+			t.Fatalf("register: %v", err)
+		}
+		if u.admin {
+			if err := ctrl.SetRole(ctx, resp.Meta.UserID, roleID); err != nil {
+				t.Fatalf("set role: %v", err)
+			}
+		}
+		*u.resp, err = ctrl.Login(ctx, model.UserLogin{Email: u.email, Password: "password"})
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+	}
+
+	return f
+}
+
+// Run runs the conformance suite against handlers created by setup.
+func Run(t *testing.T, setup Setup) {
+	t.Helper()
+
+	f := newFixture(t)
+	m := authgo.NewAuthMiddleware(jwtConfig)
+
+	t.Run("header", func(t *testing.T) {
+		h := setup(t, m)
+		tampered := f.user.Token[:len(f.user.Token)-4] + "AAAA"
+
+		tests := []struct {
+			name   string
+			path   string
+			token  string
+			status int
+			userID int64
+		}{
+			{name: "missing token", path: PathAuth, status: http.StatusUnauthorized},
+			{name: "malformed token", path: PathAuth, token: "not-a-jwt", status: http.StatusUnauthorized},
+			{name: "invalid signature", path: PathAuth, token: tampered, status: http.StatusUnauthorized},
+			{name: "valid token", path: PathAuth, token: f.user.Token, status: http.StatusOK, userID: f.user.Meta.UserID},
+			{name: "missing role", path: PathRole, token: f.user.Token, status: http.StatusForbidden},
+			{name: "role", path: PathRole, token: f.admin.Token, status: http.StatusOK, userID: f.admin.Meta.UserID},
+			{name: "missing permission", path: PathPermission, token: f.user.Token, status: http.StatusForbidden},
+			{name: "permission", path: PathPermission, token: f.admin.Token, status: http.StatusOK, userID: f.admin.Meta.UserID},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				if tt.token != "" {
+					r.Header.Set("Authorization", "Bearer "+tt.token)
+				}
+				check(t, h, r, tt.status, tt.userID)
+			})
+		}
+	})
+
+	t.Run("cookie", func(t *testing.T) {
+		cfg := authgo.CookieConfig{Insecure: true, CSRFKey: "guardtest-csrf-key"}
+		h := setup(t, m, authgo.WithCookieTransport(cfg))
+
+		token, csrf, err := authgo.AuthCookies(cfg, f.user)
+		if err != nil {
+			t.Fatalf("auth cookies: %v", err)
+		}
+		otherCSRF, err := authgo.CSRFToken(cfg, f.admin.Meta)
+		if err != nil {
+			t.Fatalf("csrf token: %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			method string
+			csrf   string
+			status int
+			userID int64
+		}{
+			{name: "safe method", method: http.MethodGet, status: http.StatusOK, userID: f.user.Meta.UserID},
+			{name: "missing csrf token", method: http.MethodPost, status: http.StatusForbidden},
+			{name: "invalid csrf token", method: http.MethodPost, csrf: csrf.Value + "x", status: http.StatusForbidden},
+			{name: "csrf token of another user", method: http.MethodPost, csrf: otherCSRF, status: http.StatusForbidden},
+			{name: "csrf token", method: http.MethodPost, csrf: csrf.Value, status: http.StatusOK, userID: f.user.Meta.UserID},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := httptest.NewRequest(tt.method, PathAuth, nil)
+				r.AddCookie(&http.Cookie{Name: token.Name, Value: token.Value})
+				r.AddCookie(&http.Cookie{Name: csrf.Name, Value: csrf.Value})
+				if tt.csrf != "" {
+					r.Header.Set("X-CSRF-Token", tt.csrf)
+				}
+				check(t, h, r, tt.status, tt.userID)
+			})
+		}
+	})
+}
+
+// check serves the request and compares the response with the expected one,
+// bodies of rejected requests must not contain details of errors.
+func check(t *testing.T, h http.Handler, r *http.Request, status int, userID int64) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	body := w.Body.String()
+
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d, body %q", w.Code, status, body)
+	}
+
+	if status == http.StatusOK {
+		if want := strconv.FormatInt(userID, 10); body != want {
+			t.Fatalf("body = %q, want user id %s", body, want)
+		}
+		return
+	}
+
+	for _, leak := range leaks {
+		if strings.Contains(body, leak) {
+			t.Fatalf("body %q exposes %q", body, leak)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package authgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+	"github.com/yogenyslav/authgo/store/memory"
+)
+
+// testSecret signs tokens of controllers created by newTestController unless cfg sets a key.
+const testSecret = "authgo-test-secret"
+
+// newTestController creates a controller over a fresh memory database.
+func newTestController(t *testing.T, cfg AuthConfig, opts ...ControllerOption) *controller {
+	t.Helper()
+
+	if cfg.Jwt.Secret == "" && cfg.Jwt.PrivateKey == "" && len(cfg.Jwt.Keys) == 0 {
+		cfg.Jwt.Secret = testSecret
+	}
+	if cfg.Jwt.Expire == 0 {
+		cfg.Jwt.Expire = 1
+	}
+
+	db := memory.NewDB()
+	ctrl, err := NewAuthController(cfg, memory.NewUserStore(db), memory.NewRoleStore(db), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctrl
+}
+
+// registerUser registers a user with name as username and local part of email.
+func registerUser(t *testing.T, ctrl *controller, name string) model.AuthResp {
+	t.Helper()
+
+	resp, err := ctrl.Register(context.Background(), model.UserRegister{
+		Email:    name + "@example.com",
+		Username: name,
+		Password: "password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// createRoles creates roles and returns their ids by name.
+func createRoles(t *testing.T, ctrl *controller, names ...string) map[string]int64 {
+	t.Helper()
+
+	ids := make(map[string]int64, len(names))
+	for _, name := range names {
+		id, err := ctrl.CreateRole(context.Background(), model.RoleCreate{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = id
+	}
+	return ids
+}
+
+// refreshStoreStub keeps refresh tokens in a slice indexed by id, transactions are no-op.
+type refreshStoreStub struct {
+	tokens []model.RefreshTokenDao
+}
+
+func (s *refreshStoreStub) ApplyMigrations() error                               { return nil }
+func (s *refreshStoreStub) StartTx(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (s *refreshStoreStub) CommitTx(context.Context) error                       { return nil }
+func (s *refreshStoreStub) RollbackTx(context.Context) error                     { return nil }
+
+func (s *refreshStoreStub) InsertOne(_ context.Context, token model.RefreshTokenDao) (int64, error) {
+	token.ID = int64(len(s.tokens) + 1)
+	token.CreatedAt = time.Now()
+	s.tokens = append(s.tokens, token)
+	return token.ID, nil
+}
+
+func (s *refreshStoreStub) FindOneByHash(_ context.Context, tokenHash string) (model.RefreshTokenDao, error) {
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return model.RefreshTokenDao{}, pgx.ErrNoRows
+}
+
+func (s *refreshStoreStub) MarkUsed(_ context.Context, tokenID int64) (bool, error) {
+	token := &s.tokens[tokenID-1]
+	if token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func (s *refreshStoreStub) RevokeFamily(_ context.Context, familyID string) error {
+	now := time.Now()
+	for i := range s.tokens {
+		if s.tokens[i].FamilyID == familyID && s.tokens[i].RevokedAt == nil {
+			s.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// inviteStoreStub keeps invites in a slice indexed by id, transactions are no-op,
+// other methods are not implemented.
+type inviteStoreStub struct {
+	store.InviteStore
+	invites []model.InviteDao
+}
+
+func (s *inviteStoreStub) ApplyMigrations() error                               { return nil }
+func (s *inviteStoreStub) StartTx(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (s *inviteStoreStub) CommitTx(context.Context) error                       { return nil }
+func (s *inviteStoreStub) RollbackTx(context.Context) error                     { return nil }
+
+func (s *inviteStoreStub) InsertOne(_ context.Context, invite model.InviteDao) (int64, error) {
+	invite.ID = int64(len(s.invites) + 1)
+	invite.CreatedAt = time.Now()
+	s.invites = append(s.invites, invite)
+	return invite.ID, nil
+}
+
+func (s *inviteStoreStub) FindOneByHash(_ context.Context, tokenHash string) (model.InviteDao, error) {
+	for _, invite := range s.invites {
+		if invite.TokenHash == tokenHash {
+			return invite, nil
+		}
+	}
+	return model.InviteDao{}, pgx.ErrNoRows
+}
+
+func (s *inviteStoreStub) MarkAccepted(_ context.Context, inviteID int64) (bool, error) {
+	invite := &s.invites[inviteID-1]
+	if invite.AcceptedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	invite.AcceptedAt = &now
+	return true, nil
+}
+
+// accessStoreStub holds a single request, transactions are no-op, other methods are not implemented.
+type accessStoreStub struct {
+	store.AccessRequestStore
+	req model.AccessRequestDao
+}
+
+func (s *accessStoreStub) ApplyMigrations() error                               { return nil }
+func (s *accessStoreStub) StartTx(ctx context.Context) (context.Context, error) { return ctx, nil }
+func (s *accessStoreStub) CommitTx(context.Context) error                       { return nil }
+func (s *accessStoreStub) RollbackTx(context.Context) error                     { return nil }
+
+func (s *accessStoreStub) FindOneByID(context.Context, int64) (model.AccessRequestDao, error) {
+	return s.req, nil
+}
+
+func (s *accessStoreStub) Decide(_ context.Context, _ int64, status model.AccessRequestStatus, actorID int64) (bool, error) {
+	if s.req.Status != model.AccessRequestPending {
+		return false, nil
+	}
+	s.req.Status = status
+	s.req.DecidedBy = actorID
+	return true, nil
+}
+
+func (s *accessStoreStub) InsertEvent(context.Context, model.AccessRequestEventDao) error {
+	return nil
+}
+
+// orgStoreStub makes users members of orgs without org roles, other methods are not implemented.
+type orgStoreStub struct {
+	store.OrgStore
+	members map[int64][]int64
+}
+
+func (s *orgStoreStub) ApplyMigrations() error {
+	return nil
+}
+
+func (s *orgStoreStub) IsMember(_ context.Context, orgID, userID int64) (bool, error) {
+	for _, id := range s.members[orgID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *orgStoreStub) ListUserRoles(context.Context, int64, int64) ([]model.RoleDao, error) {
+	return nil, nil
+}
+
+func (s *orgStoreStub) ListUserPermissions(context.Context, int64, int64) ([]model.PermissionDao, error) {
+	return nil, nil
+}
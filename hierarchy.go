@@ -0,0 +1,64 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrRoleCycle = errors.New("role inheritance cycle")
+)
+
+// AddInheritedRole makes every holder of the role also hold inherited role, e.g. "admin" inherits "editor".
+// Inheritance is transitive and is resolved when tokens are issued, so RequireRole("editor") passes for admins.
+func (ctrl *controller) AddInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error {
+	if roleID == inheritedRoleID {
+		return fmt.Errorf("add inherited role: %w", ErrRoleCycle)
+	}
+
+	ctx, err := ctrl.role.StartTx(ctx)
+	if err != nil {
+		return fmt.Errorf("role store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.role.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback role store transaction: %w", err))
+		}
+	}()
+
+	added, err := ctrl.role.AddInheritedRole(ctx, roleID, inheritedRoleID)
+	if err != nil {
+		return fmt.Errorf("add inherited role: %w", err)
+	}
+	if !added {
+		return fmt.Errorf("add inherited role: %w", ErrRoleCycle)
+	}
+
+	if err := ctrl.role.CommitTx(ctx); err != nil {
+		return fmt.Errorf("commit role transaction: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) RemoveInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error {
+	if err := ctrl.role.RemoveInheritedRole(ctx, roleID, inheritedRoleID); err != nil {
+		return fmt.Errorf("remove inherited role: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListInheritedRoles(ctx context.Context, roleID int64) ([]model.RoleDto, error) {
+	rolesDB, err := ctrl.role.ListInheritedRoles(ctx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("list inherited roles: %w", err)
+	}
+
+	roles := make([]model.RoleDto, 0, len(rolesDB))
+	for _, role := range rolesDB {
+		roles = append(roles, role.ToDto())
+	}
+	return roles, nil
+}
@@ -0,0 +1,182 @@
+package authgo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+type contextKey uint8
+
+const (
+	metaKey contextKey = iota
+)
+
+// WithMeta puts AuthMeta into context.Context.
+func WithMeta(ctx context.Context, meta model.AuthMeta) context.Context {
+	return context.WithValue(ctx, metaKey, meta)
+}
+
+// MetaFromContext returns AuthMeta put into context.Context by WithMeta or Authenticate.
+func MetaFromContext(ctx context.Context) (model.AuthMeta, bool) {
+	meta, ok := ctx.Value(metaKey).(model.AuthMeta)
+	return meta, ok
+}
+
+// ErrorResponder writes a response for a request rejected with the status (401 or 403) and the error.
+type ErrorResponder func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+// HTTPOption configures net/http middlewares.
+type HTTPOption func(cfg *httpConfig)
+
+type httpConfig struct {
+	respond ErrorResponder
+	guard   []GuardOption
+}
+
+// WithErrorResponder sets a function that writes responses for unauthenticated and forbidden requests.
+func WithErrorResponder(f ErrorResponder) HTTPOption {
+	return func(cfg *httpConfig) {
+		cfg.respond = f
+	}
+}
+
+// WithGuardOptions configures the Guard used by middlewares, e.g. enables cookie transport.
+func WithGuardOptions(opts ...GuardOption) HTTPOption {
+	return func(cfg *httpConfig) {
+		cfg.guard = append(cfg.guard, opts...)
+	}
+}
+
+func newHTTPConfig(opts []HTTPOption) httpConfig {
+	cfg := httpConfig{
+		respond: defaultErrorResponder,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// defaultErrorResponder writes status text as plain text body.
+func defaultErrorResponder(w http.ResponseWriter, _ *http.Request, status int, _ error) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", typeBearerToken)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// Authenticate is a net/http middleware that requires a valid access token in Authorization header
+// (or in the cookie, see WithCookieTransport) and puts AuthMeta into request context.
+func Authenticate(m Middleware, opts ...HTTPOption) func(http.Handler) http.Handler {
+	cfg := newHTTPConfig(opts)
+	guard := NewGuard(m, cfg.guard...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			meta, err := guard.Authenticate(NewHTTPRequest(r))
+			if err != nil {
+				cfg.respond(w, r, StatusCode(err), err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithMeta(r.Context(), meta)))
+		})
+	}
+}
+
+// RequireRole is a net/http middleware that requires the user to have the role.
+// It must be used after Authenticate.
+func RequireRole(m Middleware, role string, opts ...HTTPOption) func(http.Handler) http.Handler {
+	guard := NewGuard(m)
+	return requireHTTP(newHTTPConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRole(meta, role)
+	})
+}
+
+// RequireAnyRole is a net/http middleware that requires the user to have at least one of the roles.
+// It must be used after Authenticate.
+func RequireAnyRole(m Middleware, roles []string, opts ...HTTPOption) func(http.Handler) http.Handler {
+	guard := NewGuard(m)
+	return requireHTTP(newHTTPConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAnyRole(meta, roles...)
+	})
+}
+
+// RequireAllRoles is a net/http middleware that requires the user to have every role.
+// It must be used after Authenticate.
+func RequireAllRoles(m Middleware, roles []string, opts ...HTTPOption) func(http.Handler) http.Handler {
+	guard := NewGuard(m)
+	return requireHTTP(newHTTPConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireAllRoles(meta, roles...)
+	})
+}
+
+// RequireRoleExpr is a net/http middleware that requires roles of the user to satisfy the expression.
+// It must be used after Authenticate.
+func RequireRoleExpr(m Middleware, expr *RoleExpr, opts ...HTTPOption) func(http.Handler) http.Handler {
+	guard := NewGuard(m)
+	return requireHTTP(newHTTPConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequireRoleExpr(meta, expr)
+	})
+}
+
+// RequirePermission is a net/http middleware that requires the user to have the permission.
+// It must be used after Authenticate.
+func RequirePermission(m Middleware, permission string, opts ...HTTPOption) func(http.Handler) http.Handler {
+	guard := NewGuard(m)
+	return requireHTTP(newHTTPConfig(opts), func(meta model.AuthMeta) error {
+		return guard.RequirePermission(meta, permission)
+	})
+}
+
+// requireHTTP creates a middleware that checks AuthMeta from request context.
+func requireHTTP(cfg httpConfig, check func(meta model.AuthMeta) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			meta, ok := MetaFromContext(r.Context())
+			if !ok {
+				cfg.respond(w, r, http.StatusUnauthorized, ErrMissingJwt)
+				return
+			}
+
+			if err := check(meta); err != nil {
+				cfg.respond(w, r, StatusCode(err), err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// httpRequest adapts *http.Request to Request.
+type httpRequest struct {
+	r *http.Request
+}
+
+// NewHTTPRequest adapts *http.Request to Request, it can be used by adapters for net/http based frameworks.
+func NewHTTPRequest(r *http.Request) Request {
+	return httpRequest{r: r}
+}
+
+func (r httpRequest) Context() context.Context {
+	return r.r.Context()
+}
+
+func (r httpRequest) Header(name string) string {
+	return r.r.Header.Get(name)
+}
+
+func (r httpRequest) Cookie(name string) (string, bool) {
+	cookie, err := r.r.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+func (r httpRequest) Method() string {
+	return r.r.Method
+}
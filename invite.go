@@ -0,0 +1,298 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+)
+
+const (
+	// defaultInviteExpire is used when InviteConfig.Expire is not set (3 days).
+	defaultInviteExpire int = 24 * 3
+	// inviteTokenSize is a number of random bytes in invite token.
+	inviteTokenSize int = 32
+)
+
+var (
+	ErrInvitesDisabled  = errors.New("invites are not enabled")
+	ErrInvalidInvite    = errors.New("invalid invite")
+	ErrInviteExpired    = errors.New("invite is expired")
+	ErrInviteRevoked    = errors.New("invite is revoked")
+	ErrInviteUsed       = errors.New("invite is already accepted")
+	ErrInvalidInviteReq = errors.New("invite email is required")
+	ErrInviteRole       = errors.New("invite role does not exist")
+	ErrInviteOrg        = errors.New("invite organization does not exist")
+	ErrInviterRequired  = errors.New("inviter is required")
+	ErrInviterRole      = errors.New("inviter does not hold the invite role")
+)
+
+// WithInviteStore enables invitations: users are invited by email with a set of roles
+// and join by accepting the invite with a single-use token.
+func WithInviteStore(s store.InviteStore) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.invite = s
+	}
+}
+
+// Invite creates an invite and returns its token, which must be delivered to the invited user, e.g. by email.
+// Only the hash of the token is persisted.
+func (ctrl *controller) Invite(ctx context.Context, req model.InviteCreate) (string, error) {
+	if ctrl.invite == nil {
+		return "", ErrInvitesDisabled
+	}
+	if req.Email == "" {
+		return "", ErrInvalidInviteReq
+	}
+	if req.OrgID != 0 && ctrl.org == nil {
+		return "", ErrOrgsDisabled
+	}
+	if err := ctrl.validateInvite(ctx, req); err != nil {
+		return "", err
+	}
+
+	rawToken, err := generateToken(inviteTokenSize)
+	if err != nil {
+		return "", fmt.Errorf("generate invite token: %w", err)
+	}
+
+	expire := ctrl.cfg.Invite.Expire
+	if expire <= 0 {
+		expire = defaultInviteExpire
+	}
+
+	roleIDs := req.RoleIDs
+	if roleIDs == nil {
+		roleIDs = []int64{}
+	}
+
+	invite := model.InviteDao{
+		OrgID:     req.OrgID,
+		Email:     req.Email,
+		RoleIDs:   roleIDs,
+		TokenHash: hashToken(rawToken),
+		InvitedBy: req.InvitedBy,
+		ExpiresAt: time.Now().Add(time.Hour * time.Duration(expire)),
+	}
+	if _, err := ctrl.invite.InsertOne(ctx, invite); err != nil {
+		return "", fmt.Errorf("insert invite: %w", err)
+	}
+
+	return rawToken, nil
+}
+
+// validateInvite checks that roles of the invite exist and, for org invites, that the organization exists
+// and the inviting user is its member, so invalid invites are rejected before they are delivered.
+// The inviter must hold every role of the invite (within the organization for org invites),
+// so users can't grant roles they don't have.
+func (ctrl *controller) validateInvite(ctx context.Context, req model.InviteCreate) error {
+	if req.InvitedBy == 0 {
+		return ErrInviterRequired
+	}
+
+	for _, roleID := range req.RoleIDs {
+		if _, err := ctrl.role.FindOneByID(ctx, roleID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %d", ErrInviteRole, roleID)
+			}
+			return fmt.Errorf("find role: %w", err)
+		}
+	}
+
+	var (
+		held []model.RoleDao
+		err  error
+	)
+	if req.OrgID == 0 {
+		held, err = ctrl.role.ListUserRoles(ctx, req.InvitedBy)
+		if err != nil {
+			return fmt.Errorf("list inviter roles: %w", err)
+		}
+	} else {
+		if _, err := ctrl.org.FindOneByID(ctx, req.OrgID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %d", ErrInviteOrg, req.OrgID)
+			}
+			return fmt.Errorf("find org: %w", err)
+		}
+
+		if err := ctrl.requireMember(ctx, req.OrgID, req.InvitedBy); err != nil {
+			return fmt.Errorf("inviter: %w", err)
+		}
+
+		held, err = ctrl.org.ListUserRoles(ctx, req.OrgID, req.InvitedBy)
+		if err != nil {
+			return fmt.Errorf("list inviter org roles: %w", err)
+		}
+	}
+
+	for _, roleID := range req.RoleIDs {
+		if !slices.ContainsFunc(held, func(role model.RoleDao) bool { return role.ID == roleID }) {
+			return fmt.Errorf("%w: %d", ErrInviterRole, roleID)
+		}
+	}
+
+	return nil
+}
+
+// AcceptInvite accepts the invite and logs the user in. If a user with the invite email exists,
+// the password must match and the invite roles are attached to the user, otherwise a new user is registered.
+func (ctrl *controller) AcceptInvite(ctx context.Context, req model.InviteAccept) (model.AuthResp, error) {
+	var resp model.AuthResp
+
+	if ctrl.invite == nil {
+		return resp, ErrInvitesDisabled
+	}
+
+	ctx, err := ctrl.invite.StartTx(ctx)
+	if err != nil {
+		return resp, fmt.Errorf("invite store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.invite.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback invite store transaction: %w", err))
+		}
+	}()
+
+	invite, err := ctrl.invite.FindOneByHash(ctx, hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resp, ErrInvalidInvite
+		}
+		return resp, fmt.Errorf("find invite: %w", err)
+	}
+
+	switch {
+	case invite.RevokedAt != nil:
+		return resp, ErrInviteRevoked
+	case invite.AcceptedAt != nil:
+		return resp, ErrInviteUsed
+	case !invite.Pending(time.Now()):
+		return resp, ErrInviteExpired
+	}
+
+	accepted, err := ctrl.invite.MarkAccepted(ctx, invite.ID)
+	if err != nil {
+		return resp, fmt.Errorf("mark invite accepted: %w", err)
+	}
+	if !accepted {
+		return resp, ErrInviteUsed
+	}
+
+	userID, err := ctrl.inviteUser(ctx, invite.Email, req)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := ctrl.attachInviteRoles(ctx, invite, userID); err != nil {
+		return resp, err
+	}
+
+	meta, err := ctrl.authMeta(ctx, userID, invite.OrgID)
+	if err != nil {
+		return resp, err
+	}
+
+	meta.SessionID, err = ctrl.startSession(ctx, userID, req.Client)
+	if err != nil {
+		return resp, fmt.Errorf("start session: %w", err)
+	}
+
+	resp, err = ctrl.issueTokens(ctx, meta, "")
+	if err != nil {
+		return resp, err
+	}
+
+	if err = ctrl.invite.CommitTx(ctx); err != nil {
+		return resp, fmt.Errorf("commit invite transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// inviteUser returns id of the existing user with the email or registers a new one.
+func (ctrl *controller) inviteUser(ctx context.Context, email string, req model.InviteAccept) (int64, error) {
+	user, err := ctrl.user.FindOneByEmail(ctx, email)
+	if err == nil {
+		if !verifyPassword(user.HashPassword, req.Password) {
+			return 0, fmt.Errorf("verify password: %w", ErrInvalidPassword)
+		}
+		return user.ID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("find user: %w", err)
+	}
+
+	hashedPassword, err := hashPassword(req.Password)
+	if err != nil {
+		return 0, fmt.Errorf("hash password: %w", err)
+	}
+
+	return ctrl.createUser(ctx, model.UserDao{
+		Email:        email,
+		HashPassword: hashedPassword,
+		Username:     req.Username,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		MiddleName:   req.MiddleName,
+	})
+}
+
+// attachInviteRoles assigns roles of the invite to the user, within organization for org invites.
+func (ctrl *controller) attachInviteRoles(ctx context.Context, invite model.InviteDao, userID int64) error {
+	if invite.OrgID == 0 {
+		for _, roleID := range invite.RoleIDs {
+			if err := ctrl.user.SetRole(ctx, userID, roleID); err != nil {
+				return fmt.Errorf("set role: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	if err := ctrl.org.AddMember(ctx, invite.OrgID, userID); err != nil {
+		return fmt.Errorf("add member: %w", err)
+	}
+	for _, roleID := range invite.RoleIDs {
+		if err := ctrl.org.SetRole(ctx, invite.OrgID, userID, roleID); err != nil {
+			return fmt.Errorf("set org role: %w", err)
+		}
+	}
+	return nil
+}
+
+func (ctrl *controller) RevokeInvite(ctx context.Context, inviteID int64) error {
+	if ctrl.invite == nil {
+		return ErrInvitesDisabled
+	}
+
+	if err := ctrl.invite.Revoke(ctx, inviteID); err != nil {
+		return fmt.Errorf("revoke invite: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListPendingInvites(ctx context.Context, orgID int64) ([]model.InviteDto, error) {
+	if ctrl.invite == nil {
+		return nil, ErrInvitesDisabled
+	}
+
+	invitesDB, err := ctrl.invite.ListPending(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list pending invites: %w", err)
+	}
+
+	invites := make([]model.InviteDto, 0, len(invitesDB))
+	for _, invite := range invitesDB {
+		invites = append(invites, invite.ToDto())
+	}
+	return invites, nil
+}
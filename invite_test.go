@@ -0,0 +1,115 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestInviteValidatesRoles(t *testing.T) {
+	ctx := context.Background()
+
+	invites := &inviteStoreStub{}
+	ctrl := newTestController(t, AuthConfig{}, WithInviteStore(invites))
+
+	roles := createRoles(t, ctrl, "editor", "admin")
+	roleID, adminID := roles["editor"], roles["admin"]
+	inviter := registerUser(t, ctrl, "lead")
+	if err := ctrl.SetRole(ctx, inviter.Meta.UserID, roleID); err != nil {
+		t.Fatal(err)
+	}
+	inviterID := inviter.Meta.UserID
+
+	for name, tt := range map[string]struct {
+		req  model.InviteCreate
+		want error
+	}{
+		"missing role": {
+			req:  model.InviteCreate{Email: "user@example.com", RoleIDs: []int64{roleID, roleID + 100}, InvitedBy: inviterID},
+			want: ErrInviteRole,
+		},
+		"without inviter": {
+			req:  model.InviteCreate{Email: "user@example.com", RoleIDs: []int64{roleID}},
+			want: ErrInviterRequired,
+		},
+		"role the inviter doesn't hold": {
+			req:  model.InviteCreate{Email: "user@example.com", RoleIDs: []int64{roleID, adminID}, InvitedBy: inviterID},
+			want: ErrInviterRole,
+		},
+		"org invite": {
+			req:  model.InviteCreate{Email: "user@example.com", OrgID: 1, InvitedBy: inviterID},
+			want: ErrOrgsDisabled,
+		},
+	} {
+		if _, err := ctrl.Invite(ctx, tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tt.want)
+		}
+	}
+	if len(invites.invites) != 0 {
+		t.Fatalf("invalid invite was inserted")
+	}
+
+	req := model.InviteCreate{Email: "user@example.com", RoleIDs: []int64{roleID}, InvitedBy: inviterID}
+	if _, err := ctrl.Invite(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(invites.invites) != 1 {
+		t.Fatalf("inserted %d invites, want 1", len(invites.invites))
+	}
+}
+
+func TestAcceptInvite(t *testing.T) {
+	ctx := context.Background()
+
+	invites := &inviteStoreStub{}
+	ctrl := newTestController(t, AuthConfig{}, WithInviteStore(invites))
+
+	editorID := createRoles(t, ctrl, "editor")["editor"]
+	inviter := registerUser(t, ctrl, "lead")
+	if err := ctrl.SetRole(ctx, inviter.Meta.UserID, editorID); err != nil {
+		t.Fatal(err)
+	}
+
+	invite := func(email string) string {
+		t.Helper()
+
+		token, err := ctrl.Invite(ctx, model.InviteCreate{Email: email, RoleIDs: []int64{editorID}, InvitedBy: inviter.Meta.UserID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	token := invite("user@example.com")
+	resp, err := ctrl.AcceptInvite(ctx, model.InviteAccept{Token: token, Password: "password", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roleExpiry(t, ctrl, resp.Meta.UserID, editorID) != nil {
+		t.Errorf("invite role is not permanent")
+	}
+
+	if _, err := ctrl.AcceptInvite(ctx, model.InviteAccept{Token: token, Password: "password"}); !errors.Is(err, ErrInviteUsed) {
+		t.Errorf("used invite: got %v, want ErrInviteUsed", err)
+	}
+
+	expired := invite("late@example.com")
+	invites.invites[len(invites.invites)-1].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := ctrl.AcceptInvite(ctx, model.InviteAccept{Token: expired, Password: "password"}); !errors.Is(err, ErrInviteExpired) {
+		t.Errorf("expired invite: got %v, want ErrInviteExpired", err)
+	}
+
+	revoked := invite("revoked@example.com")
+	now := time.Now()
+	invites.invites[len(invites.invites)-1].RevokedAt = &now
+	if _, err := ctrl.AcceptInvite(ctx, model.InviteAccept{Token: revoked, Password: "password"}); !errors.Is(err, ErrInviteRevoked) {
+		t.Errorf("revoked invite: got %v, want ErrInviteRevoked", err)
+	}
+
+	if _, err := ctrl.AcceptInvite(ctx, model.InviteAccept{Token: "unknown", Password: "password"}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("unknown invite: got %v, want ErrInvalidInvite", err)
+	}
+}
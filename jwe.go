@@ -0,0 +1,218 @@
+package authgo
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultContentEncryption is used when EncryptionConfig.Content is not set.
+	defaultContentEncryption = jose.A256GCM
+	// defaultPassphraseIterations is used when EncryptionConfig.Iterations is not set.
+	defaultPassphraseIterations int = 600_000
+	// minPassphraseSaltSize is a minimal length of EncryptionConfig.Salt in bytes.
+	minPassphraseSaltSize int = 16
+)
+
+var (
+	ErrUnsupportedEncryption = errors.New("unsupported token encryption algorithm")
+	ErrInvalidEncryptionKey  = errors.New("invalid token encryption key")
+	ErrNoEncryptionKey       = errors.New("no key to encrypt tokens")
+	ErrNoDecryptionKey       = errors.New("no key to decrypt tokens")
+	ErrInvalidPassphraseSalt = errors.New("passphrase requires a unique salt of at least 16 bytes")
+)
+
+// legacyContentEncryption maps sizes of raw keys of the legacy scalar encryption config to content encryption.
+var legacyContentEncryption = map[int]jose.ContentEncryption{
+	16: jose.A128GCM,
+	24: jose.A192GCM,
+	32: jose.A256GCM,
+}
+
+// UnmarshalYAML accepts the legacy scalar form `encryption: <raw AES key>` in addition to the mapping,
+// raw 16, 24 or 32 byte key is used with dir algorithm and AES-GCM of the same size.
+// Tokens encrypted before JWE support are not accepted anyway, so only the config is migrated.
+func (c *EncryptionConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	var legacyKey string
+	if err := unmarshal(&legacyKey); err == nil {
+		if legacyKey == "" {
+			*c = EncryptionConfig{}
+			return nil
+		}
+
+		enc, ok := legacyContentEncryption[len(legacyKey)]
+		if !ok {
+			return fmt.Errorf("%w: legacy encryption key must be 16, 24 or 32 bytes", ErrInvalidEncryptionKey)
+		}
+		*c = EncryptionConfig{
+			Algorithm: string(jose.DIRECT),
+			Content:   string(enc),
+			Key:       base64.StdEncoding.EncodeToString([]byte(legacyKey)),
+		}
+		return nil
+	}
+
+	type plain EncryptionConfig
+	return unmarshal((*plain)(c))
+}
+
+// symmetricKeySizes are sizes of symmetric keys in bytes for key management algorithms, except "dir".
+var symmetricKeySizes = map[jose.KeyAlgorithm]int{
+	jose.A128KW:    16,
+	jose.A192KW:    24,
+	jose.A256KW:    32,
+	jose.A128GCMKW: 16,
+	jose.A192GCMKW: 24,
+	jose.A256GCMKW: 32,
+}
+
+// contentKeySizes are sizes of content encryption keys in bytes, used by "dir" algorithm.
+var contentKeySizes = map[jose.ContentEncryption]int{
+	jose.A128GCM:       16,
+	jose.A192GCM:       24,
+	jose.A256GCM:       32,
+	jose.A128CBC_HS256: 32,
+	jose.A192CBC_HS384: 48,
+	jose.A256CBC_HS512: 64,
+}
+
+// tokenEncrypter wraps signed tokens into compact JWE (RFC 7516).
+type tokenEncrypter struct {
+	alg        jose.KeyAlgorithm
+	enc        jose.ContentEncryption
+	encrypter  jose.Encrypter
+	decryptKey any
+}
+
+// newTokenEncrypter validates encryption config and prepares keys. Returns nil if encryption is disabled.
+func newTokenEncrypter(cfg EncryptionConfig) (*tokenEncrypter, error) {
+	alg := jose.KeyAlgorithm(cfg.Algorithm)
+	if alg == "" {
+		if cfg.Key == "" && cfg.Passphrase == "" {
+			return nil, nil
+		}
+		alg = jose.DIRECT
+	}
+
+	enc := jose.ContentEncryption(cfg.Content)
+	if enc == "" {
+		enc = defaultContentEncryption
+	}
+	if _, ok := contentKeySizes[enc]; !ok {
+		return nil, fmt.Errorf("%w: content encryption %q", ErrUnsupportedEncryption, enc)
+	}
+
+	var (
+		encryptKey, decryptKey any
+		err                    error
+	)
+	switch alg {
+	case jose.DIRECT:
+		encryptKey, err = symmetricKey(cfg, contentKeySizes[enc])
+		decryptKey = encryptKey
+	case jose.A128KW, jose.A192KW, jose.A256KW, jose.A128GCMKW, jose.A192GCMKW, jose.A256GCMKW:
+		encryptKey, err = symmetricKey(cfg, symmetricKeySizes[alg])
+		decryptKey = encryptKey
+	case jose.RSA_OAEP, jose.RSA_OAEP_256:
+		decryptKey, encryptKey, err = parseRSAKeys(cfg.PrivateKey, cfg.PublicKey)
+	case jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW:
+		decryptKey, encryptKey, err = parseECDSAKeys(nil, cfg.PrivateKey, cfg.PublicKey)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncryption, alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", alg, err)
+	}
+
+	opts := (&jose.EncrypterOptions{}).WithContentType("JWT")
+	encrypter, err := jose.NewEncrypter(enc, jose.Recipient{Algorithm: alg, Key: encryptKey}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncryptionKey, err)
+	}
+
+	return &tokenEncrypter{
+		alg:        alg,
+		enc:        enc,
+		encrypter:  encrypter,
+		decryptKey: decryptKey,
+	}, nil
+}
+
+// symmetricKey decodes base64 key from config or derives it from passphrase with PBKDF2.
+func symmetricKey(cfg EncryptionConfig, size int) ([]byte, error) {
+	if cfg.Passphrase != "" {
+		// a fixed salt would let one precomputed dictionary attack every deployment
+		if len(cfg.Salt) < minPassphraseSaltSize {
+			return nil, ErrInvalidPassphraseSalt
+		}
+
+		iterations := cfg.Iterations
+		if iterations <= 0 {
+			iterations = defaultPassphraseIterations
+		}
+
+		key, err := pbkdf2.Key(sha256.New, cfg.Passphrase, []byte(cfg.Salt), iterations, size)
+		if err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+		return key, nil
+	}
+
+	if cfg.Key == "" {
+		return nil, ErrNoEncryptionKey
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.Key)
+	if err != nil {
+		key, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(cfg.Key, "="))
+		if err != nil {
+			return nil, fmt.Errorf("%w: key is not base64 encoded", ErrInvalidEncryptionKey)
+		}
+	}
+
+	if len(key) != size {
+		return nil, fmt.Errorf("%w: expected %d bytes key, got %d", ErrInvalidEncryptionKey, size, len(key))
+	}
+
+	return key, nil
+}
+
+// encrypt wraps signed token into compact JWE.
+func (e *tokenEncrypter) encrypt(signedToken string) (string, error) {
+	if e.encrypter == nil {
+		return "", ErrNoEncryptionKey
+	}
+
+	obj, err := e.encrypter.Encrypt([]byte(signedToken))
+	if err != nil {
+		return "", fmt.Errorf("encrypt token: %w", err)
+	}
+
+	return obj.CompactSerialize()
+}
+
+// decrypt extracts signed token from compact JWE.
+func (e *tokenEncrypter) decrypt(encryptedToken string) (string, error) {
+	if e.decryptKey == nil {
+		return "", ErrNoDecryptionKey
+	}
+
+	obj, err := jose.ParseEncryptedCompact(encryptedToken, []jose.KeyAlgorithm{e.alg}, []jose.ContentEncryption{e.enc})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", jwt.ErrTokenMalformed, err)
+	}
+
+	signedToken, err := obj.Decrypt(e.decryptKey)
+	if err != nil {
+		return "", fmt.Errorf("decrypt token: %w", err)
+	}
+
+	return string(signedToken), nil
+}
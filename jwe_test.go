@@ -0,0 +1,131 @@
+package authgo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+const jweTestPayload = "header.payload.signature"
+
+func TestTokenEncrypterRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}))
+
+	randomKey := func(size int) string {
+		key := make([]byte, size)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(key)
+	}
+
+	algs := []jose.KeyAlgorithm{
+		jose.DIRECT,
+		jose.A128KW, jose.A192KW, jose.A256KW,
+		jose.A128GCMKW, jose.A192GCMKW, jose.A256GCMKW,
+		jose.RSA_OAEP, jose.RSA_OAEP_256,
+		jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW,
+	}
+	for _, alg := range algs {
+		for enc, size := range contentKeySizes {
+			t.Run(string(alg)+"/"+string(enc), func(t *testing.T) {
+				cfg := EncryptionConfig{Algorithm: string(alg), Content: string(enc)}
+				switch alg {
+				case jose.DIRECT:
+					cfg.Key = randomKey(size)
+				case jose.RSA_OAEP, jose.RSA_OAEP_256:
+					cfg.PrivateKey = rsaPEM
+				case jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW:
+					cfg.PrivateKey = ecPEM
+				default:
+					cfg.Key = randomKey(symmetricKeySizes[alg])
+				}
+
+				e, err := newTokenEncrypter(cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encrypted, err := e.encrypt(jweTestPayload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				decrypted, err := e.decrypt(encrypted)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if decrypted != jweTestPayload {
+					t.Errorf("decrypted %q, want %q", decrypted, jweTestPayload)
+				}
+			})
+		}
+	}
+}
+
+func TestTokenEncrypterPassphrase(t *testing.T) {
+	cfg := EncryptionConfig{
+		Algorithm:  string(jose.A256KW),
+		Passphrase: "jwe-test-passphrase",
+		Salt:       "jwe-test-salt-0123456789",
+		Iterations: 1000,
+	}
+
+	e, err := newTokenEncrypter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := e.encrypt(jweTestPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same, err := newTokenEncrypter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted, err := same.decrypt(encrypted); err != nil || decrypted != jweTestPayload {
+		t.Fatalf("same passphrase and salt: got %q, %v", decrypted, err)
+	}
+
+	wrongSalt := cfg
+	wrongSalt.Salt = "jwe-test-salt-9876543210"
+	wrongPassphrase := cfg
+	wrongPassphrase.Passphrase = "jwe-test-wrong-passphrase"
+
+	for name, wrong := range map[string]EncryptionConfig{"salt": wrongSalt, "passphrase": wrongPassphrase} {
+		d, err := newTokenEncrypter(wrong)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.decrypt(encrypted); err == nil {
+			t.Errorf("wrong %s: token is decrypted", name)
+		}
+	}
+
+	shortSalt := cfg
+	shortSalt.Salt = "short"
+	if _, err := newTokenEncrypter(shortSalt); !errors.Is(err, ErrInvalidPassphraseSalt) {
+		t.Errorf("short salt: got %v, want ErrInvalidPassphraseSalt", err)
+	}
+}
@@ -3,7 +3,7 @@ package authgo
 import (
 	"errors"
 	"fmt"
-	"strconv"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,73 +12,221 @@ import (
 
 const (
 	typeBearerToken string = "Bearer"
+	// tokenIDSize is a number of random bytes in token id (jti).
+	tokenIDSize int = 16
 )
 
 var (
-	ErrJwtSignMethod = errors.New("unexpected signing method")
+	ErrJwtSignMethod        = errors.New("unexpected signing method")
+	ErrTokenMalformed       = errors.New("token is malformed")
+	ErrTokenSignature       = errors.New("token signature is invalid")
+	ErrTokenExpired         = errors.New("token is expired")
+	ErrTokenNotYetValid     = errors.New("token is not valid yet")
+	ErrTokenIssuedInFuture  = errors.New("token is issued in the future")
+	ErrTokenInvalidIssuer   = errors.New("token has invalid issuer")
+	ErrTokenInvalidAudience = errors.New("token has invalid audience")
+	ErrTokenMissingClaim    = errors.New("token is missing required claim")
+	ErrReservedClaim        = errors.New("claim name is reserved")
 )
 
+// reservedClaims are claims set by authgo, which can't be overridden with custom claims.
+var reservedClaims = map[string]struct{}{
+	"iss":       {},
+	"sub":       {},
+	"aud":       {},
+	"exp":       {},
+	"nbf":       {},
+	"iat":       {},
+	"jti":       {},
+	"sid":       {},
+	"roles":     {},
+	"perms":     {},
+	"org":       {},
+	"org_roles": {},
+	"org_perms": {},
+}
+
+// tokenErrors maps errors of jwt package to sentinel errors of authgo.
+var tokenErrors = []struct {
+	jwtErr error
+	err    error
+}{
+	{jwt.ErrTokenMalformed, ErrTokenMalformed},
+	{jwt.ErrTokenSignatureInvalid, ErrTokenSignature},
+	{jwt.ErrTokenUnverifiable, ErrTokenSignature},
+	{jwt.ErrTokenExpired, ErrTokenExpired},
+	{jwt.ErrTokenNotValidYet, ErrTokenNotYetValid},
+	{jwt.ErrTokenUsedBeforeIssued, ErrTokenIssuedInFuture},
+	{jwt.ErrTokenInvalidIssuer, ErrTokenInvalidIssuer},
+	{jwt.ErrTokenInvalidAudience, ErrTokenInvalidAudience},
+	{jwt.ErrTokenRequiredClaimMissing, ErrTokenMissingClaim},
+}
+
 type jwtProvider struct {
-	secret     string
+	keys       *Keyring
 	expire     int
-	encryption []byte
+	issuer     string
+	audience   []string
+	notBefore  time.Duration
+	leeway     time.Duration
+	parserOpts []jwt.ParserOption
+	validator  *jwt.Validator
+	encryption *tokenEncrypter
+	cache      *tokenCache
 }
 
-func newJwtProvider(cfg JwtConfig) *jwtProvider {
-	var encryption []byte
-	if cfg.Encryption != "" {
-		encryption = []byte(cfg.Encryption)
+// newJwtProvider creates jwtProvider over the keyring, or over a new keyring from config if keys is nil.
+func newJwtProvider(cfg JwtConfig, keys *Keyring) (*jwtProvider, error) {
+	var err error
+
+	if keys == nil {
+		keys, err = NewKeyring(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("keyring: %w", err)
+		}
+	}
+
+	encryption, err := newTokenEncrypter(cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("token encryption: %w", err)
+	}
+
+	leeway := time.Second * time.Duration(cfg.Leeway)
+	parserOpts := []jwt.ParserOption{
+		jwt.WithLeeway(leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.Issuer))
 	}
 
 	return &jwtProvider{
-		secret:     cfg.Secret,
+		keys:       keys,
 		expire:     cfg.Expire,
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+		notBefore:  time.Second * time.Duration(cfg.NotBefore),
+		leeway:     leeway,
+		parserOpts: parserOpts,
+		validator:  jwt.NewValidator(parserOpts...),
 		encryption: encryption,
-	}
+	}, nil
 }
 
 func (j *jwtProvider) createAccessToken(meta model.AuthMeta) (string, error) {
-	key := []byte(j.secret)
+	key, err := j.keys.signing()
+	if err != nil {
+		return "", err
+	}
+
+	if meta.TokenID == "" {
+		meta.TokenID, err = generateToken(tokenIDSize)
+		if err != nil {
+			return "", fmt.Errorf("generate token id: %w", err)
+		}
+	}
 
-	jwtClaims := jwt.MapClaims{
-		"exp":   jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(j.expire))),
-		"sub":   strconv.FormatInt(meta.UserID, 10),
-		"roles": meta.Roles,
+	claims, err := newAccessClaims(meta)
+	if err != nil {
+		return "", err
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	signedToken, err := accessToken.SignedString(key)
+	now := time.Now()
+	expiresAt := now.Add(time.Hour * time.Duration(j.expire))
+	// privileges of time-bound roles must not outlive their grants
+	if rolesExpire, ok := rolesExpireAt(meta.Roles); ok && rolesExpire.Before(expiresAt) {
+		expiresAt = rolesExpire
+	}
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now.Add(j.notBefore))
+	claims.Issuer = j.issuer
+	claims.Audience = j.audience
+
+	accessToken := jwt.NewWithClaims(key.method, claims)
+	accessToken.Header["kid"] = key.id
+	signedToken, err := accessToken.SignedString(key.sign)
 	if err != nil {
 		return "", fmt.Errorf("sign token: %w", err)
 	}
 
 	if j.encryption != nil {
-		return encrypt(signedToken, j.encryption)
+		return j.encryption.encrypt(signedToken)
 	}
 
 	return signedToken, nil
 }
 
-func (j *jwtProvider) parseAccessToken(accessTokenString string) (*jwt.Token, error) {
+// parse decrypts the token if needed and verifies its signature with the keyring.
+func (j *jwtProvider) parse(accessTokenString string, opts ...jwt.ParserOption) (*accessClaims, error) {
 	var err error
 
 	if j.encryption != nil {
-		accessTokenString, err = decrypt(accessTokenString, j.encryption)
+		accessTokenString, err = j.encryption.decrypt(accessTokenString)
 		if err != nil {
 			return nil, fmt.Errorf("decrypt token: %w", err)
 		}
 	}
 
-	accessToken, err := jwt.Parse(accessTokenString, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrJwtSignMethod
+	claims := &accessClaims{}
+	if _, err := jwt.ParseWithClaims(accessTokenString, claims, j.keys.keyfunc, opts...); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// parseAccessToken parses the token and validates its registered claims.
+// Errors wrap one of the sentinel token errors describing the failed check.
+// If token cache is enabled, signature of the already verified token is not checked again.
+func (j *jwtProvider) parseAccessToken(accessTokenString string) (*accessClaims, error) {
+	var (
+		claims *accessClaims
+		err    error
+	)
+
+	if j.cache != nil {
+		claims = j.cache.get(accessTokenString, j.keys.version())
+	}
+
+	if claims != nil {
+		err = j.validator.Validate(claims)
+	} else {
+		version := j.keys.version()
+		claims, err = j.parse(accessTokenString, j.parserOpts...)
+		if err == nil && j.cache != nil {
+			j.cache.put(accessTokenString, claims, version)
 		}
-		return []byte(j.secret), nil
-	})
+	}
 
 	if err != nil {
+		for _, e := range tokenErrors {
+			if errors.Is(err, e.jwtErr) {
+				return nil, fmt.Errorf("%w: %w", e.err, err)
+			}
+		}
 		return nil, err
 	}
 
-	return accessToken, nil
+	if err := j.validateAudience(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validateAudience checks that the token is intended for at least one of the configured audiences.
+func (j *jwtProvider) validateAudience(claims *accessClaims) error {
+	if len(j.audience) == 0 {
+		return nil
+	}
+
+	for _, aud := range claims.Audience {
+		if slices.Contains(j.audience, aud) {
+			return nil
+		}
+	}
+
+	return ErrTokenInvalidAudience
 }
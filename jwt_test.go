@@ -0,0 +1,52 @@
+package authgo
+
+import (
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func BenchmarkParseAccessToken(b *testing.B) {
+	cfg := JwtConfig{
+		Secret: "benchmark-secret-benchmark-secret",
+		Expire: 1,
+	}
+	meta := model.AuthMeta{
+		UserID:      42,
+		SessionID:   "session",
+		Roles:       []model.RoleDto{{ID: 1, Name: model.DefaultRole}, {ID: 2, Name: "admin"}},
+		Permissions: []string{"users:read", "users:write"},
+		Extra:       map[string]any{"tenant": "acme", "plan": "pro"},
+	}
+
+	for _, bc := range []struct {
+		name      string
+		cacheSize int
+	}{
+		{name: "no cache"},
+		{name: "cache", cacheSize: 128},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			j, err := newJwtProvider(cfg, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if bc.cacheSize > 0 {
+				j.cache = newTokenCache(bc.cacheSize)
+			}
+
+			token, err := j.createAccessToken(meta)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				if _, err := j.parseAccessToken(token); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package authgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultKeyID is an id of the key defined by top-level fields of JwtConfig.
+	defaultKeyID string = "default"
+)
+
+var (
+	ErrEmptyKeyID       = errors.New("key id is empty")
+	ErrKeyNotFound      = errors.New("key not found")
+	ErrKeyExists        = errors.New("key already exists")
+	ErrNoActiveKey      = errors.New("no active signing key")
+	ErrRetireActiveKey  = errors.New("active key can't be retired")
+	ErrVerificationOnly = errors.New("key can only verify tokens")
+)
+
+// Keyring holds a set of keys identified by ids (kid).
+// One key is active and signs new tokens, other keys are still accepted to verify tokens until retired.
+// Keyring is safe for concurrent use, so the same instance can be shared between controller and middleware
+// to rotate keys at runtime.
+type Keyring struct {
+	mu     sync.RWMutex
+	keys   map[string]signingKey
+	active string
+	// retired is incremented every time a key is retired.
+	retired uint64
+}
+
+// NewKeyring creates a keyring from config.
+// Top-level key fields of JwtConfig define a key with id JwtConfig.KeyID ("default" if empty),
+// JwtConfig.Keys define additional keys. JwtConfig.ActiveKey selects a signing key,
+// by default it is the top-level key or the first key from JwtConfig.Keys that can sign tokens.
+func NewKeyring(cfg JwtConfig) (*Keyring, error) {
+	k := &Keyring{
+		keys: make(map[string]signingKey),
+	}
+
+	keys := cfg.Keys
+	if cfg.Secret != "" || cfg.PrivateKey != "" || cfg.PublicKey != "" {
+		keyID := cfg.KeyID
+		if keyID == "" {
+			keyID = defaultKeyID
+		}
+		keys = append([]KeyConfig{{
+			ID:         keyID,
+			Algorithm:  cfg.Algorithm,
+			Secret:     cfg.Secret,
+			PrivateKey: cfg.PrivateKey,
+			PublicKey:  cfg.PublicKey,
+		}}, keys...)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoVerificationKey
+	}
+
+	for _, keyCfg := range keys {
+		if err := k.AddKey(keyCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	active := cfg.ActiveKey
+	if active == "" {
+		for _, keyCfg := range keys {
+			if k.keys[keyCfg.ID].sign != nil {
+				active = keyCfg.ID
+				break
+			}
+		}
+	}
+
+	if active != "" {
+		if err := k.SetActive(active); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// AddKey adds a new key to the keyring. The key can be used to verify tokens immediately,
+// but signs new tokens only after SetActive.
+func (k *Keyring) AddKey(cfg KeyConfig) error {
+	if cfg.ID == "" {
+		return fmt.Errorf("add key: %w", ErrEmptyKeyID)
+	}
+
+	key, err := newSigningKey(cfg.Algorithm, cfg.Secret, cfg.PrivateKey, cfg.PublicKey)
+	if err != nil {
+		return fmt.Errorf("key %q: %w", cfg.ID, err)
+	}
+	key.id = cfg.ID
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[cfg.ID]; ok {
+		return fmt.Errorf("key %q: %w", cfg.ID, ErrKeyExists)
+	}
+	k.keys[cfg.ID] = key
+
+	return nil
+}
+
+// SetActive makes the key with given id sign new tokens. The previous active key is kept for verification.
+func (k *Keyring) SetActive(keyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return fmt.Errorf("key %q: %w", keyID, ErrKeyNotFound)
+	}
+
+	if key.sign == nil {
+		return fmt.Errorf("key %q: %w", keyID, ErrVerificationOnly)
+	}
+
+	k.active = keyID
+	return nil
+}
+
+// RetireKey removes the key from the keyring, so tokens signed by it are no longer accepted.
+func (k *Keyring) RetireKey(keyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[keyID]; !ok {
+		return fmt.Errorf("key %q: %w", keyID, ErrKeyNotFound)
+	}
+
+	if keyID == k.active {
+		return fmt.Errorf("key %q: %w", keyID, ErrRetireActiveKey)
+	}
+
+	delete(k.keys, keyID)
+	k.retired++
+	return nil
+}
+
+// ActiveKeyID returns id of the key that signs new tokens.
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.active
+}
+
+// KeyIDs returns sorted ids of all keys accepted for verification.
+func (k *Keyring) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// version changes every time tokens verified before may become invalid.
+func (k *Keyring) version() uint64 {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.retired
+}
+
+// signing returns the active key.
+func (k *Keyring) signing() (signingKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[k.active]
+	if !ok {
+		return key, ErrNoActiveKey
+	}
+
+	return key, nil
+}
+
+// keyfunc selects a verification key by kid header of the token.
+// Tokens without kid are checked against every key with the same algorithm.
+func (k *Keyring) keyfunc(token *jwt.Token) (any, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	alg := token.Method.Alg()
+
+	if keyID, ok := token.Header["kid"].(string); ok {
+		key, ok := k.keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("key %q: %w", keyID, ErrKeyNotFound)
+		}
+		if key.method.Alg() != alg {
+			return nil, ErrJwtSignMethod
+		}
+		return key.verify, nil
+	}
+
+	var set jwt.VerificationKeySet
+	for _, key := range k.keys {
+		if key.method.Alg() == alg {
+			set.Keys = append(set.Keys, key.verify)
+		}
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, ErrJwtSignMethod
+	}
+
+	return set, nil
+}
@@ -0,0 +1,72 @@
+package authgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		cacheSize int
+	}{
+		{name: "no cache"},
+		{name: "cache", cacheSize: 16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := NewKeyring(JwtConfig{KeyID: "old", Secret: "keyring-test-old-secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			j, err := newJwtProvider(JwtConfig{Expire: 1}, keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.cacheSize > 0 {
+				j.cache = newTokenCache(tc.cacheSize)
+			}
+
+			oldToken, err := j.createAccessToken(model.AuthMeta{UserID: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := j.parseAccessToken(oldToken); err != nil {
+				t.Fatalf("old token before rotation: %v", err)
+			}
+
+			if err := keys.AddKey(KeyConfig{ID: "new", Secret: "keyring-test-new-secret"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := keys.SetActive("new"); err != nil {
+				t.Fatal(err)
+			}
+			if err := keys.RetireKey("new"); !errors.Is(err, ErrRetireActiveKey) {
+				t.Fatalf("retire active key: got %v, want ErrRetireActiveKey", err)
+			}
+
+			newToken, err := j.createAccessToken(model.AuthMeta{UserID: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := j.parseAccessToken(oldToken); err != nil {
+				t.Fatalf("old token after rotation: %v", err)
+			}
+
+			if err := keys.RetireKey("old"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := j.parseAccessToken(oldToken); !errors.Is(err, ErrKeyNotFound) {
+				t.Fatalf("token with retired kid: got %v, want ErrKeyNotFound", err)
+			}
+			claims, err := j.parseAccessToken(newToken)
+			if err != nil {
+				t.Fatalf("new token after retirement: %v", err)
+			}
+			if claims.Subject != "2" {
+				t.Errorf("subject = %q, want 2", claims.Subject)
+			}
+		})
+	}
+}
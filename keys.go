@@ -0,0 +1,170 @@
+package authgo
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultAlgorithm is used when JwtConfig.Algorithm is not set.
+	defaultAlgorithm string = "HS256"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrNoSigningKey         = errors.New("no key to sign tokens")
+	ErrNoVerificationKey    = errors.New("no key to verify tokens")
+	ErrKeyCurveMismatch     = errors.New("key curve does not match signing algorithm")
+)
+
+// signingKey holds key material for one signing algorithm.
+// sign may be nil if the key can only be used to verify tokens.
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	sign   any
+	verify any
+}
+
+// newSigningKey parses key material for the algorithm: a shared secret for HMAC algorithms,
+// or PEM encoded private and/or public keys for RSA, ECDSA and Ed25519 algorithms.
+func newSigningKey(algorithm, secret, privateKey, publicKey string) (signingKey, error) {
+	var key signingKey
+
+	if algorithm == "" {
+		algorithm = defaultAlgorithm
+	}
+
+	key.method = jwt.GetSigningMethod(algorithm)
+	if key.method == nil || key.method == jwt.SigningMethodNone {
+		return key, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	var err error
+	switch method := key.method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if secret == "" {
+			return key, fmt.Errorf("%s: %w", algorithm, ErrNoSigningKey)
+		}
+		key.sign = []byte(secret)
+		key.verify = []byte(secret)
+		return key, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		key.sign, key.verify, err = parseRSAKeys(privateKey, publicKey)
+	case *jwt.SigningMethodECDSA:
+		key.sign, key.verify, err = parseECDSAKeys(method, privateKey, publicKey)
+	case *jwt.SigningMethodEd25519:
+		key.sign, key.verify, err = parseEd25519Keys(privateKey, publicKey)
+	default:
+		return key, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+	if err != nil {
+		return key, fmt.Errorf("%s: %w", algorithm, err)
+	}
+
+	return key, nil
+}
+
+func parseRSAKeys(privatePEM, publicPEM string) (any, any, error) {
+	var sign, verify any
+
+	if privatePEM != "" {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(strings.TrimSpace(privatePEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse private key: %w", err)
+		}
+		sign, verify = private, &private.PublicKey
+	}
+
+	if publicPEM != "" {
+		public, err := jwt.ParseRSAPublicKeyFromPEM([]byte(strings.TrimSpace(publicPEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse public key: %w", err)
+		}
+		verify = public
+	}
+
+	if verify == nil {
+		return nil, nil, ErrNoVerificationKey
+	}
+
+	return sign, verify, nil
+}
+
+func parseECDSAKeys(method *jwt.SigningMethodECDSA, privatePEM, publicPEM string) (any, any, error) {
+	var sign, verify any
+
+	if privatePEM != "" {
+		private, err := jwt.ParseECPrivateKeyFromPEM([]byte(strings.TrimSpace(privatePEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse private key: %w", err)
+		}
+		if err := checkCurve(method, &private.PublicKey); err != nil {
+			return nil, nil, fmt.Errorf("private key: %w", err)
+		}
+		sign, verify = private, &private.PublicKey
+	}
+
+	if publicPEM != "" {
+		public, err := jwt.ParseECPublicKeyFromPEM([]byte(strings.TrimSpace(publicPEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse public key: %w", err)
+		}
+		if err := checkCurve(method, public); err != nil {
+			return nil, nil, fmt.Errorf("public key: %w", err)
+		}
+		verify = public
+	}
+
+	if verify == nil {
+		return nil, nil, ErrNoVerificationKey
+	}
+
+	return sign, verify, nil
+}
+
+// checkCurve checks that the key is on the curve of the algorithm, e.g. P-256 for ES256, nil method accepts any curve.
+func checkCurve(method *jwt.SigningMethodECDSA, key *ecdsa.PublicKey) error {
+	if method == nil {
+		return nil
+	}
+	if bits := key.Curve.Params().BitSize; bits != method.CurveBits {
+		return fmt.Errorf("%w: %s requires P-%d, got P-%d", ErrKeyCurveMismatch, method.Alg(), method.CurveBits, bits)
+	}
+	return nil
+}
+
+func parseEd25519Keys(privatePEM, publicPEM string) (any, any, error) {
+	var sign, verify any
+
+	if privatePEM != "" {
+		private, err := jwt.ParseEdPrivateKeyFromPEM([]byte(strings.TrimSpace(privatePEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse private key: %w", err)
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, nil, fmt.Errorf("parse private key: %w", jwt.ErrInvalidKeyType)
+		}
+		sign, verify = signer, signer.Public()
+	}
+
+	if publicPEM != "" {
+		public, err := jwt.ParseEdPublicKeyFromPEM([]byte(strings.TrimSpace(publicPEM)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse public key: %w", err)
+		}
+		verify = public
+	}
+
+	if verify == nil {
+		return nil, nil, ErrNoVerificationKey
+	}
+
+	return sign, verify, nil
+}
@@ -1,13 +1,14 @@
 package authgo
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/policy"
+	"github.com/yogenyslav/authgo/store"
 )
 
 var (
@@ -16,16 +17,82 @@ var (
 )
 
 type middleware struct {
-	jwt *jwtProvider
+	jwt        *jwtProvider
+	session    store.SessionStore
+	revocation store.RevocationList
+	keys       *Keyring
+	cacheSize  int
+	policy     *policy.Policy
 }
 
+// MiddlewareOption configures optional checks of the middleware.
+type MiddlewareOption func(m *middleware)
+
+// WithSessionValidation makes middleware reject tokens whose session was ended.
+func WithSessionValidation(s store.SessionStore) MiddlewareOption {
+	return func(m *middleware) {
+		m.session = s
+	}
+}
+
+// WithVerificationKeyring makes middleware verify tokens with the shared keyring instead of keys from config.
+func WithVerificationKeyring(k *Keyring) MiddlewareOption {
+	return func(m *middleware) {
+		m.keys = k
+	}
+}
+
+// WithTokenCache enables a bounded LRU cache of verified tokens, so signature of the same token
+// is checked only once. Expiration, revocation and session checks are still performed on every request.
+func WithTokenCache(size int) MiddlewareOption {
+	return func(m *middleware) {
+		m.cacheSize = size
+	}
+}
+
+// NewAuthMiddleware creates a middleware without optional checks, it panics if key material in cfg is invalid.
+// Use NewAuthMiddlewareWithOptions to configure the middleware and handle the error.
 func NewAuthMiddleware(cfg JwtConfig) *middleware {
-	return &middleware{
-		jwt: newJwtProvider(cfg),
+	m, err := NewAuthMiddlewareWithOptions(cfg)
+	if err != nil {
+		panic(err)
 	}
+	return m
+}
+
+// NewAuthMiddlewareWithOptions creates a middleware, which needs only public key material when asymmetric algorithm is used.
+func NewAuthMiddlewareWithOptions(cfg JwtConfig, opts ...MiddlewareOption) (*middleware, error) {
+	m := &middleware{}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	jwt, err := newJwtProvider(cfg, m.keys)
+	if err != nil {
+		return nil, fmt.Errorf("jwt provider: %w", err)
+	}
+	m.jwt = jwt
+	m.keys = jwt.keys
+	if m.cacheSize > 0 {
+		m.jwt.cache = newTokenCache(m.cacheSize)
+	}
+
+	return m, nil
+}
+
+// Keyring returns the keyring used to verify tokens, so keys can be rotated at runtime.
+func (m *middleware) Keyring() *Keyring {
+	return m.keys
 }
 
+// RequireAuth validates the token from authorization header with context.Background(),
+// it is kept for compatibility, use RequireAuthContext to pass request context to revocation and session stores.
 func (m *middleware) RequireAuth(authHeader string) (model.AuthMeta, error) {
+	return m.RequireAuthContext(context.Background(), authHeader)
+}
+
+// RequireAuthContext validates the token from authorization header, ctx is passed to revocation and session stores.
+func (m *middleware) RequireAuthContext(ctx context.Context, authHeader string) (model.AuthMeta, error) {
 	var meta model.AuthMeta
 
 	rawToken := strings.Split(authHeader, " ")
@@ -33,19 +100,26 @@ func (m *middleware) RequireAuth(authHeader string) (model.AuthMeta, error) {
 		return meta, fmt.Errorf("parse authorization header: %w", ErrMissingJwt)
 	}
 
-	accessToken, err := m.jwt.parseAccessToken(rawToken[1])
+	claims, err := m.jwt.parseAccessToken(rawToken[1])
 	if err != nil {
 		return meta, fmt.Errorf("invalid token: %w", err)
 	}
 
-	claims := accessToken.Claims.(jwt.MapClaims)
-	rawClaims, err := json.Marshal(claims)
+	meta, err = claims.toMeta()
 	if err != nil {
-		return meta, fmt.Errorf("marshal token claims: %w", err)
+		return meta, fmt.Errorf("invalid token: %w", err)
 	}
 
-	if err := json.Unmarshal(rawClaims, &meta); err != nil {
-		return meta, fmt.Errorf("unmarshal token claims: %w", err)
+	if m.revocation != nil {
+		if err := checkRevoked(ctx, m.revocation, meta.TokenID); err != nil {
+			return model.AuthMeta{}, fmt.Errorf("validate token: %w", err)
+		}
+	}
+
+	if m.session != nil {
+		if err := validateSession(ctx, m.session, meta.SessionID); err != nil {
+			return model.AuthMeta{}, fmt.Errorf("validate session: %w", err)
+		}
 	}
 
 	return meta, nil
@@ -58,5 +132,35 @@ func (m *middleware) RequireRole(meta model.AuthMeta, requiredRole string) error
 		}
 	}
 
-	return ErrForbidden
+	return &RoleError{Requirement: fmt.Sprintf("%q", requiredRole), Missing: []string{requiredRole}}
+}
+
+func (m *middleware) RequireAnyRole(meta model.AuthMeta, roles ...string) error {
+	set := newRoleSet(meta.Roles)
+	for _, role := range roles {
+		if _, ok := set[role]; ok {
+			return nil
+		}
+	}
+
+	return &RoleError{Requirement: fmt.Sprintf("any of %q", roles), Missing: roles}
+}
+
+func (m *middleware) RequireAllRoles(meta model.AuthMeta, roles ...string) error {
+	set := newRoleSet(meta.Roles)
+	err := &RoleError{Requirement: fmt.Sprintf("all of %q", roles)}
+	for _, role := range roles {
+		if _, ok := set[role]; !ok {
+			err.add(role, false)
+		}
+	}
+
+	if len(err.Missing) > 0 {
+		return err
+	}
+	return nil
+}
+
+func (m *middleware) RequireRoleExpr(meta model.AuthMeta, expr *RoleExpr) error {
+	return expr.Check(meta.Roles)
 }
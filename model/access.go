@@ -0,0 +1,105 @@
+package model
+
+import "time"
+
+// AccessRequestStatus is a state of access request.
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending   AccessRequestStatus = "pending"
+	AccessRequestApproved  AccessRequestStatus = "approved"
+	AccessRequestDenied    AccessRequestStatus = "denied"
+	AccessRequestCancelled AccessRequestStatus = "cancelled"
+	AccessRequestExpired   AccessRequestStatus = "expired"
+)
+
+// AccessRequestDao is an access request model in data store.
+type AccessRequestDao struct {
+	ID     int64 `db:"id"`
+	UserID int64 `db:"user_id"`
+	RoleID int64 `db:"role_id"`
+	// RoleName is a name of the role at the moment of request, it is kept after the role is deleted.
+	RoleName string `db:"role_name"`
+	Reason   string `db:"reason"`
+	// Duration is a lifetime of the role grant in seconds.
+	Duration  int64               `db:"duration"`
+	Status    AccessRequestStatus `db:"status"`
+	CreatedAt time.Time           `db:"created_at"`
+	// ExpiresAt is a deadline for decision on the request.
+	ExpiresAt time.Time  `db:"expires_at"`
+	DecidedBy int64      `db:"decided_by"`
+	DecidedAt *time.Time `db:"decided_at"`
+}
+
+// ToDto converts an access request data model into logical model for access request.
+func (r *AccessRequestDao) ToDto() AccessRequestDto {
+	return AccessRequestDto{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		RoleID:    r.RoleID,
+		RoleName:  r.RoleName,
+		Reason:    r.Reason,
+		Duration:  time.Second * time.Duration(r.Duration),
+		Status:    r.Status,
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		DecidedBy: r.DecidedBy,
+		DecidedAt: r.DecidedAt,
+	}
+}
+
+// AccessRequestDto is logical model for access request.
+type AccessRequestDto struct {
+	ID        int64               `json:"id"`
+	UserID    int64               `json:"user_id"`
+	RoleID    int64               `json:"role_id"`
+	RoleName  string              `json:"role_name"`
+	Reason    string              `json:"reason"`
+	Duration  time.Duration       `json:"duration"`
+	Status    AccessRequestStatus `json:"status"`
+	CreatedAt time.Time           `json:"created_at"`
+	ExpiresAt time.Time           `json:"expires_at"`
+	DecidedBy int64               `json:"decided_by,omitempty"`
+	DecidedAt *time.Time          `json:"decided_at,omitempty"`
+}
+
+// AccessRequestCreate is a model of a RequestAccess request.
+type AccessRequestCreate struct {
+	UserID int64
+	RoleID int64
+	// Duration is a requested lifetime of the role grant.
+	Duration time.Duration
+	Reason   string
+}
+
+// AccessRequestEventDao is a record of an access request state change in data store.
+type AccessRequestEventDao struct {
+	ID        int64               `db:"id"`
+	RequestID int64               `db:"request_id"`
+	ActorID   int64               `db:"actor_id"`
+	Status    AccessRequestStatus `db:"status"`
+	Comment   string              `db:"comment"`
+	CreatedAt time.Time           `db:"created_at"`
+}
+
+// ToDto converts an access request event data model into logical model for access request event.
+func (e *AccessRequestEventDao) ToDto() AccessRequestEventDto {
+	return AccessRequestEventDto{
+		ID:        e.ID,
+		RequestID: e.RequestID,
+		ActorID:   e.ActorID,
+		Status:    e.Status,
+		Comment:   e.Comment,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// AccessRequestEventDto is logical model for access request event.
+type AccessRequestEventDto struct {
+	ID        int64               `json:"id"`
+	RequestID int64               `json:"request_id"`
+	ActorID   int64               `json:"actor_id"`
+	Status    AccessRequestStatus `json:"status"`
+	Comment   string              `json:"comment"`
+	CreatedAt time.Time           `json:"created_at"`
+}
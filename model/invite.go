@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// InviteDao is an invite model in data store.
+type InviteDao struct {
+	ID         int64      `db:"id"`
+	OrgID      int64      `db:"org_id"`
+	Email      string     `db:"email"`
+	RoleIDs    []int64    `db:"role_ids"`
+	TokenHash  string     `db:"token_hash"`
+	InvitedBy  int64      `db:"invited_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	AcceptedAt *time.Time `db:"accepted_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+// Pending reports whether the invite is neither accepted, revoked nor expired at the given moment.
+func (i *InviteDao) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// ToDto converts an invite data model into logical model for invite.
+func (i *InviteDao) ToDto() InviteDto {
+	return InviteDto{
+		ID:        i.ID,
+		OrgID:     i.OrgID,
+		Email:     i.Email,
+		RoleIDs:   i.RoleIDs,
+		InvitedBy: i.InvitedBy,
+		CreatedAt: i.CreatedAt,
+		ExpiresAt: i.ExpiresAt,
+	}
+}
+
+// InviteDto is logical model for invite.
+type InviteDto struct {
+	ID        int64     `json:"id"`
+	OrgID     int64     `json:"org_id"`
+	Email     string    `json:"email"`
+	RoleIDs   []int64   `json:"role_ids"`
+	InvitedBy int64     `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// InviteCreate is a model of an Invite request. Roles are org-scoped when OrgID is set, otherwise global.
+// InvitedBy is required and must hold every role of the invite, for org invites it must be a member
+// of the organization holding the roles within it.
+type InviteCreate struct {
+	OrgID     int64
+	Email     string
+	RoleIDs   []int64
+	InvitedBy int64
+}
+
+// InviteAccept is a model of an AcceptInvite request. Password authenticates an existing user
+// or is set for a new one together with the rest of the fields.
+type InviteAccept struct {
+	Token      string
+	Password   string
+	Username   string
+	FirstName  string
+	LastName   string
+	MiddleName string
+	Client     ClientInfo
+}
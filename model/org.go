@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// OrganizationDao is an organization model in data store.
+type OrganizationDao struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Slug      string    `db:"slug"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ToDto converts an organization data model into logical model for organization.
+func (o *OrganizationDao) ToDto() OrganizationDto {
+	return OrganizationDto{
+		ID:   o.ID,
+		Name: o.Name,
+		Slug: o.Slug,
+	}
+}
+
+// OrganizationDto is logical model for organization.
+type OrganizationDto struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// OrgCreate is a request model for creating organization.
+type OrgCreate struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
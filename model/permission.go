@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// PermissionDao is a permission model in data store.
+// Permission name consists of segments separated by ":", e.g. "invoices:write".
+type PermissionDao struct {
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ToDto converts a permission data model into logical model for permission.
+func (p *PermissionDao) ToDto() PermissionDto {
+	return PermissionDto{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+	}
+}
+
+// PermissionDto is logical model for permission.
+type PermissionDto struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -9,21 +9,51 @@ const (
 
 // RoleDat is a data model for role.
 type RoleDao struct {
-	ID        int64     `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	DisplayName string    `db:"display_name"`
+	Description string    `db:"description"`
+	IsSystem    bool      `db:"is_system"`
+	CreatedAt   time.Time `db:"created_at"`
+	// ExpiresAt is set for roles of a user held by time-bound grants.
+	ExpiresAt *time.Time `db:"expires_at"`
 }
 
 // ToDto converts a role data model into logical model for role.
 func (r *RoleDao) ToDto() RoleDto {
 	return RoleDto{
-		ID:   r.ID,
-		Name: r.Name,
+		ID:          r.ID,
+		Name:        r.Name,
+		DisplayName: r.DisplayName,
+		Description: r.Description,
+		IsSystem:    r.IsSystem,
+		ExpiresAt:   r.ExpiresAt,
 	}
 }
 
-// RoleDto is logical model for role.
+// RoleDto is logical model for role. Roles in access tokens carry only id, name and expiration of the grant.
 type RoleDto struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	DisplayName string     `json:"display_name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	IsSystem    bool       `json:"is_system,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// RoleCreate is a model of a CreateRole request.
+type RoleCreate struct {
+	Name        string
+	DisplayName string
+	Description string
+}
+
+// RoleGrant is a model of a SetRoleUntil request.
+type RoleGrant struct {
+	UserID    int64
+	RoleID    int64
+	ExpiresAt time.Time
+	// GrantedBy is an id of the user who granted the role.
+	GrantedBy int64
+	Reason    string
 }
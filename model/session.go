@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// SessionDao is a session model in data store.
+type SessionDao struct {
+	ID         string     `db:"id"`
+	UserID     int64      `db:"user_id"`
+	UserAgent  string     `db:"user_agent"`
+	IP         string     `db:"ip"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastSeenAt time.Time  `db:"last_seen_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	EndedAt    *time.Time `db:"ended_at"`
+}
+
+// Active reports whether the session is neither ended nor expired at the given moment.
+func (s *SessionDao) Active(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ToDto converts a session data model into logical model for session.
+func (s *SessionDao) ToDto() SessionDto {
+	return SessionDto{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+// SessionDto is a logical model for session.
+type SessionDto struct {
+	ID         string    `json:"id"`
+	UserID     int64     `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
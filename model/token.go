@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// RefreshTokenDao is a refresh token model in data store.
+type RefreshTokenDao struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	SessionID string     `db:"session_id"`
+	OrgID     int64      `db:"org_id"`
+	FamilyID  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
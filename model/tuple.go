@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidTuple = errors.New("invalid relation tuple")
+)
+
+// Object is an object of relation tuple, e.g. "document:42".
+type Object struct {
+	Namespace string `json:"namespace"`
+	ID        string `json:"id"`
+}
+
+// String formats the object as "namespace:id".
+func (o Object) String() string {
+	return o.Namespace + ":" + o.ID
+}
+
+// ParseObject parses an object from "namespace:id".
+func ParseObject(s string) (Object, error) {
+	namespace, id, ok := strings.Cut(s, ":")
+	if !ok || namespace == "" || id == "" || strings.ContainsAny(namespace, "#@") || strings.ContainsAny(id, "#@") {
+		return Object{}, fmt.Errorf("%w: object %q", ErrInvalidTuple, s)
+	}
+	return Object{Namespace: namespace, ID: id}, nil
+}
+
+// Subject is a subject of relation tuple: either an object, e.g. "user:7",
+// or a userset, i.e. all subjects having the relation to the object, e.g. "group:1#member".
+type Subject struct {
+	Object
+	Relation string `json:"relation,omitempty"`
+}
+
+// String formats the subject as "namespace:id" or "namespace:id#relation".
+func (s Subject) String() string {
+	if s.Relation == "" {
+		return s.Object.String()
+	}
+	return s.Object.String() + "#" + s.Relation
+}
+
+// ParseSubject parses a subject from "namespace:id" or "namespace:id#relation".
+func ParseSubject(s string) (Subject, error) {
+	object, relation, _ := strings.Cut(s, "#")
+	o, err := ParseObject(object)
+	if err != nil {
+		return Subject{}, err
+	}
+	if strings.Contains(s, "#") && relation == "" {
+		return Subject{}, fmt.Errorf("%w: subject %q", ErrInvalidTuple, s)
+	}
+	return Subject{Object: o, Relation: relation}, nil
+}
+
+// RelationTuple states that the subject has the relation to the object, e.g. "document:42#viewer@user:7".
+type RelationTuple struct {
+	Object   Object  `json:"object"`
+	Relation string  `json:"relation"`
+	Subject  Subject `json:"subject"`
+}
+
+// String formats the tuple as "namespace:id#relation@subject".
+func (t RelationTuple) String() string {
+	return t.Object.String() + "#" + t.Relation + "@" + t.Subject.String()
+}
+
+// ParseRelationTuple parses a tuple from "namespace:id#relation@subject".
+func ParseRelationTuple(s string) (RelationTuple, error) {
+	objectRelation, subject, ok := strings.Cut(s, "@")
+	if !ok {
+		return RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidTuple, s)
+	}
+
+	object, relation, ok := strings.Cut(objectRelation, "#")
+	if !ok || relation == "" {
+		return RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidTuple, s)
+	}
+
+	o, err := ParseObject(object)
+	if err != nil {
+		return RelationTuple{}, err
+	}
+	sub, err := ParseSubject(subject)
+	if err != nil {
+		return RelationTuple{}, err
+	}
+
+	return RelationTuple{Object: o, Relation: relation, Subject: sub}, nil
+}
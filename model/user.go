@@ -49,23 +49,48 @@ type UserRegister struct {
 	FirstName  string
 	LastName   string
 	MiddleName string
+	Client     ClientInfo
 }
 
 // UserLogin is a model of a Login request.
 type UserLogin struct {
 	Email    string
 	Password string
+	// OrgID is an organization to log in, zero means no active organization.
+	OrgID  int64
+	Client ClientInfo
+}
+
+// ClientInfo describes the client that starts an auth session.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
 }
 
 // AuthMeta is a model with data used to validate user's identity and permissions during requests.
 type AuthMeta struct {
-	UserID int64     `json:"sub"`
-	Roles  []RoleDto `json:"roles"`
+	UserID    int64  `json:"sub,string"`
+	SessionID string `json:"sid,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+	// OrgID is an active organization.
+	OrgID int64 `json:"org,omitempty"`
+	// Roles are global roles of the user.
+	Roles []RoleDto `json:"roles"`
+	// Permissions are names of permissions granted by the global roles.
+	Permissions []string `json:"perms,omitempty"`
+	// OrgRoles are roles of the user within the active organization, they never grant global access.
+	OrgRoles []RoleDto `json:"org_roles,omitempty"`
+	// OrgPermissions are names of permissions granted by OrgRoles.
+	OrgPermissions []string `json:"org_perms,omitempty"`
+	// Extra holds custom claims, which are stored as top-level claims of the token.
+	// Integer numbers of parsed claims are int64, other numbers are float64.
+	Extra map[string]any `json:"-"`
 }
 
 // AuthResp is a general response model for requests Login and Register.
 type AuthResp struct {
-	Token string
-	Type  string
-	Meta  AuthMeta
+	Token        string
+	RefreshToken string
+	Type         string
+	Meta         AuthMeta
 }
@@ -0,0 +1,246 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+)
+
+var (
+	ErrOrgsDisabled  = errors.New("organizations are not enabled")
+	ErrNotOrgMember  = errors.New("user is not a member of the organization")
+	ErrInvalidOrgReq = errors.New("organization name and slug are required")
+)
+
+// WithOrgStore enables organizations: users are members of multiple organizations with org-scoped roles,
+// and access tokens carry the active organization.
+func WithOrgStore(s store.OrgStore) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.org = s
+	}
+}
+
+// addOrgMeta sets the active organization with roles and permissions of the user in it.
+// They are kept in OrgRoles and OrgPermissions, so an org admin is not a global admin.
+func (ctrl *controller) addOrgMeta(ctx context.Context, meta *model.AuthMeta, orgID int64) error {
+	if err := ctrl.requireMember(ctx, orgID, meta.UserID); err != nil {
+		return err
+	}
+
+	rolesDB, err := ctrl.org.ListUserRoles(ctx, orgID, meta.UserID)
+	if err != nil {
+		return fmt.Errorf("list org user roles: %w", err)
+	}
+	meta.OrgRoles = make([]model.RoleDto, 0, len(rolesDB))
+	for _, role := range rolesDB {
+		meta.OrgRoles = append(meta.OrgRoles, role.ToDto())
+	}
+
+	permissionsDB, err := ctrl.org.ListUserPermissions(ctx, orgID, meta.UserID)
+	if err != nil {
+		return fmt.Errorf("list org user permissions: %w", err)
+	}
+	meta.OrgPermissions = make([]string, 0, len(permissionsDB))
+	for _, permission := range permissionsDB {
+		meta.OrgPermissions = append(meta.OrgPermissions, permission.Name)
+	}
+
+	meta.OrgID = orgID
+	return nil
+}
+
+// requireOrg checks that the token is scoped to the organization.
+func requireOrg(meta model.AuthMeta, orgID int64) error {
+	if orgID == 0 || meta.OrgID != orgID {
+		return fmt.Errorf("%w: token is not scoped to organization %d", ErrForbidden, orgID)
+	}
+	return nil
+}
+
+func (m *middleware) RequireOrgRole(meta model.AuthMeta, orgID int64, requiredRole string) error {
+	if err := requireOrg(meta, orgID); err != nil {
+		return err
+	}
+
+	for _, role := range meta.OrgRoles {
+		if role.Name == requiredRole {
+			return nil
+		}
+	}
+
+	return &RoleError{Requirement: fmt.Sprintf("%q in organization %d", requiredRole, orgID), Missing: []string{requiredRole}}
+}
+
+func (m *middleware) RequireOrgPermission(meta model.AuthMeta, orgID int64, permission string) error {
+	if err := requireOrg(meta, orgID); err != nil {
+		return err
+	}
+
+	for _, granted := range meta.OrgPermissions {
+		if matchPermission(granted, permission) {
+			return nil
+		}
+	}
+
+	return &PermissionError{Permission: permission}
+}
+
+// requireMember returns ErrNotOrgMember if the user is not a member of the organization.
+func (ctrl *controller) requireMember(ctx context.Context, orgID, userID int64) error {
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	member, err := ctrl.org.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("check org member: %w", err)
+	}
+	if !member {
+		return ErrNotOrgMember
+	}
+	return nil
+}
+
+func (ctrl *controller) CreateOrg(ctx context.Context, req model.OrgCreate) (int64, error) {
+	if ctrl.org == nil {
+		return 0, ErrOrgsDisabled
+	}
+	if req.Name == "" || req.Slug == "" {
+		return 0, ErrInvalidOrgReq
+	}
+
+	orgID, err := ctrl.org.InsertOne(ctx, model.OrganizationDao{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert organization: %w", err)
+	}
+	return orgID, nil
+}
+
+func (ctrl *controller) DeleteOrg(ctx context.Context, orgID int64) error {
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	if err := ctrl.org.DeleteOne(ctx, orgID); err != nil {
+		return fmt.Errorf("delete organization: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListUserOrgs(ctx context.Context, userID int64) ([]model.OrganizationDto, error) {
+	if ctrl.org == nil {
+		return nil, ErrOrgsDisabled
+	}
+
+	orgsDB, err := ctrl.org.ListUserOrgs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user organizations: %w", err)
+	}
+
+	orgs := make([]model.OrganizationDto, 0, len(orgsDB))
+	for _, org := range orgsDB {
+		orgs = append(orgs, org.ToDto())
+	}
+	return orgs, nil
+}
+
+func (ctrl *controller) AddMember(ctx context.Context, orgID, userID int64) error {
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	if err := ctrl.org.AddMember(ctx, orgID, userID); err != nil {
+		return fmt.Errorf("add member: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	if err := ctrl.org.RemoveMember(ctx, orgID, userID); err != nil {
+		return fmt.Errorf("remove member: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) SetOrgRole(ctx context.Context, orgID, userID, roleID int64) error {
+	if err := ctrl.requireMember(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	if err := ctrl.org.SetRole(ctx, orgID, userID, roleID); err != nil {
+		return fmt.Errorf("set org role: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) RemoveOrgRole(ctx context.Context, orgID, userID, roleID int64) error {
+	if ctrl.org == nil {
+		return ErrOrgsDisabled
+	}
+
+	if err := ctrl.org.RemoveRole(ctx, orgID, userID, roleID); err != nil {
+		return fmt.Errorf("remove org role: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListOrgUserRoles(ctx context.Context, orgID, userID int64) ([]model.RoleDto, error) {
+	if ctrl.org == nil {
+		return nil, ErrOrgsDisabled
+	}
+
+	rolesDB, err := ctrl.org.ListUserRoles(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list org user roles: %w", err)
+	}
+
+	roles := make([]model.RoleDto, 0, len(rolesDB))
+	for _, role := range rolesDB {
+		roles = append(roles, role.ToDto())
+	}
+	return roles, nil
+}
+
+// SwitchOrg issues new tokens for the same session with another active organization, zero orgID clears it.
+// If refresh tokens are enabled, refreshToken of the caller is rotated, so the new refresh token stays
+// in the same family and the old one can't be used anymore. Access tokens issued before keep their organization
+// until expiration.
+func (ctrl *controller) SwitchOrg(
+	ctx context.Context,
+	meta model.AuthMeta,
+	orgID int64,
+	refreshToken string,
+) (model.AuthResp, error) {
+	var resp model.AuthResp
+
+	if ctrl.refresh != nil {
+		return ctrl.rotateRefreshToken(ctx, refreshToken, func(token model.RefreshTokenDao) (int64, error) {
+			if token.UserID != meta.UserID || token.SessionID != meta.SessionID {
+				return 0, ErrInvalidRefreshToken
+			}
+			return orgID, nil
+		})
+	}
+
+	if err := ctrl.continueSession(ctx, meta.SessionID); err != nil {
+		return resp, fmt.Errorf("continue session: %w", err)
+	}
+
+	switched, err := ctrl.authMeta(ctx, meta.UserID, orgID)
+	if err != nil {
+		return resp, err
+	}
+	switched.SessionID = meta.SessionID
+
+	return ctrl.issueTokens(ctx, switched, "")
+}
@@ -0,0 +1,81 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestRequireOrgRole(t *testing.T) {
+	m := &middleware{}
+	meta := model.AuthMeta{
+		UserID:         1,
+		OrgID:          10,
+		Roles:          []model.RoleDto{{ID: 1, Name: model.DefaultRole}},
+		OrgRoles:       []model.RoleDto{{ID: 2, Name: "admin"}},
+		OrgPermissions: []string{"projects:*"},
+	}
+
+	if err := m.RequireOrgRole(meta, 10, "admin"); err != nil {
+		t.Errorf("org role: %v", err)
+	}
+	if err := m.RequireOrgPermission(meta, 10, "projects:write"); err != nil {
+		t.Errorf("org permission: %v", err)
+	}
+
+	for name, err := range map[string]error{
+		"org role as global role":       m.RequireRole(meta, "admin"),
+		"org permission as global":      m.RequirePermission(meta, "projects:write"),
+		"org role in another org":       m.RequireOrgRole(meta, 11, "admin"),
+		"org permission in another org": m.RequireOrgPermission(meta, 11, "projects:write"),
+		"global role as org role":       m.RequireOrgRole(meta, 10, model.DefaultRole),
+		"org role without org":          m.RequireOrgRole(model.AuthMeta{OrgRoles: meta.OrgRoles}, 0, "admin"),
+	} {
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: got %v, want ErrForbidden", name, err)
+		}
+	}
+}
+
+func TestSwitchOrgRotatesRefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	refresh := &refreshStoreStub{}
+	orgs := &orgStoreStub{members: make(map[int64][]int64)}
+	ctrl := newTestController(t, AuthConfig{}, WithRefreshTokenStore(refresh), WithOrgStore(orgs))
+
+	user := registerUser(t, ctrl, "user")
+	other := registerUser(t, ctrl, "other")
+	orgs.members[10] = []int64{user.Meta.UserID}
+
+	if _, err := ctrl.SwitchOrg(ctx, user.Meta, 10, other.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh token of another user: got %v, want ErrInvalidRefreshToken", err)
+	}
+
+	switched, err := ctrl.SwitchOrg(ctx, user.Meta, 10, user.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if switched.Meta.OrgID != 10 {
+		t.Errorf("org id = %d, want 10", switched.Meta.OrgID)
+	}
+
+	family := refresh.tokens[0].FamilyID
+	if last := refresh.tokens[len(refresh.tokens)-1]; last.FamilyID != family || last.OrgID != 10 {
+		t.Errorf("new refresh token: family %q, org %d, want %q, 10", last.FamilyID, last.OrgID, family)
+	}
+
+	// the refresh token used for the switch can't be used to mint tokens of the old org
+	if _, err := ctrl.Refresh(ctx, user.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("old refresh token: got %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := ctrl.Refresh(ctx, switched.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("family after reuse: got %v, want ErrRefreshTokenRevoked", err)
+	}
+
+	if _, err := ctrl.SwitchOrg(ctx, other.Meta, 10, other.RefreshToken); !errors.Is(err, ErrNotOrgMember) {
+		t.Fatalf("not a member: got %v, want ErrNotOrgMember", err)
+	}
+}
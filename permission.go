@@ -0,0 +1,117 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+const (
+	// permissionSeparator separates segments of permission name, e.g. "invoices:write".
+	permissionSeparator string = ":"
+	// permissionWildcard matches any segment, in the last position it matches any number of segments.
+	permissionWildcard string = "*"
+)
+
+var (
+	ErrInvalidPermission = errors.New("invalid permission")
+)
+
+// PermissionError is returned when the user doesn't have the permission, it wraps ErrForbidden.
+type PermissionError struct {
+	Permission string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("permission %q is not granted", e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrForbidden
+}
+
+// validatePermission checks that permission consists of non-empty segments without spaces.
+func validatePermission(permission string) error {
+	if permission == "" || strings.ContainsAny(permission, " \t\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidPermission, permission)
+	}
+	for _, segment := range strings.Split(permission, permissionSeparator) {
+		if segment == "" || segment != permissionWildcard && strings.Contains(segment, permissionWildcard) {
+			return fmt.Errorf("%w: %q", ErrInvalidPermission, permission)
+		}
+	}
+	return nil
+}
+
+// matchPermission reports whether granted permission implies the required one.
+// Wildcard segment of granted permission matches any segment, e.g. "invoices:*:read" matches "invoices:2024:read",
+// and trailing wildcard matches the rest, e.g. "invoices:*" matches "invoices:write" and "*" matches everything.
+// Wildcard in required permission is matched literally, so "invoices:*" is required only by "invoices:*" or "*".
+func matchPermission(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+
+	g := strings.Split(granted, permissionSeparator)
+	r := strings.Split(required, permissionSeparator)
+	for i, segment := range g {
+		if i >= len(r) {
+			return false
+		}
+		if segment != permissionWildcard {
+			if segment != r[i] {
+				return false
+			}
+			continue
+		}
+		if i == len(g)-1 {
+			return true
+		}
+	}
+
+	return len(g) == len(r)
+}
+
+func (m *middleware) RequirePermission(meta model.AuthMeta, permission string) error {
+	for _, granted := range meta.Permissions {
+		if matchPermission(granted, permission) {
+			return nil
+		}
+	}
+
+	return &PermissionError{Permission: permission}
+}
+
+func (ctrl *controller) GrantPermission(ctx context.Context, roleID int64, permission string) error {
+	if err := validatePermission(permission); err != nil {
+		return err
+	}
+
+	if err := ctrl.role.GrantPermission(ctx, roleID, permission); err != nil {
+		return fmt.Errorf("grant permission: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) RevokePermission(ctx context.Context, roleID int64, permission string) error {
+	if err := ctrl.role.RevokePermission(ctx, roleID, permission); err != nil {
+		return fmt.Errorf("revoke permission: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *controller) ListRolePermissions(ctx context.Context, roleID int64) ([]model.PermissionDto, error) {
+	permissionsDB, err := ctrl.role.ListRolePermissions(ctx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("list role permissions: %w", err)
+	}
+
+	permissions := make([]model.PermissionDto, 0, len(permissionsDB))
+	for _, permission := range permissionsDB {
+		permissions = append(permissions, permission.ToDto())
+	}
+	return permissions, nil
+}
@@ -0,0 +1,86 @@
+package authgo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/policy"
+)
+
+var (
+	ErrPolicyDisabled = errors.New("policy is not configured")
+)
+
+// PolicyError is returned when the policy denies access, it wraps ErrForbidden.
+type PolicyError struct {
+	Action  string
+	Reasons []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("action %q is not allowed: %s", e.Action, strings.Join(e.Reasons, "; "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrForbidden
+}
+
+// WithPolicy enables attribute-based access checks with Authorize.
+func WithPolicy(p *policy.Policy) MiddlewareOption {
+	return func(m *middleware) {
+		m.policy = p
+	}
+}
+
+func (m *middleware) Authorize(meta model.AuthMeta, action string, resource map[string]any) error {
+	if m.policy == nil {
+		return ErrPolicyDisabled
+	}
+
+	decision := m.policy.Evaluate(policy.Request{
+		Subject:  policy.Subject(meta),
+		Resource: resource,
+		Action:   action,
+	})
+	if !decision.Allowed {
+		return &PolicyError{Action: action, Reasons: decision.Reasons}
+	}
+
+	return nil
+}
+
+// ResourceLoader returns attributes of the resource addressed by the request, e.g. a document with its owner.
+type ResourceLoader func(r *http.Request) (map[string]any, error)
+
+// RequirePolicy is a net/http middleware that requires the policy to allow the action on the resource
+// returned by the loader, loader errors are responded with 403. It must be used after Authenticate.
+func RequirePolicy(m Middleware, action string, load ResourceLoader, opts ...HTTPOption) func(http.Handler) http.Handler {
+	cfg := newHTTPConfig(opts)
+	guard := NewGuard(m)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			meta, ok := MetaFromContext(r.Context())
+			if !ok {
+				cfg.respond(w, r, http.StatusUnauthorized, ErrMissingJwt)
+				return
+			}
+
+			resource, err := load(r)
+			if err != nil {
+				cfg.respond(w, r, http.StatusForbidden, fmt.Errorf("load resource: %w", err))
+				return
+			}
+
+			if err := guard.Authorize(meta, action, resource); err != nil {
+				cfg.respond(w, r, StatusCode(err), err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package policy
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// node is a node of compiled expression.
+type node interface {
+	eval(vars map[string]any) (any, error)
+}
+
+type literalNode struct {
+	v any
+}
+
+func (n literalNode) eval(map[string]any) (any, error) {
+	return n.v, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n identNode) eval(vars map[string]any) (any, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: variable %q is not set", ErrEval, n.name)
+	}
+	return normalize(v), nil
+}
+
+type selectNode struct {
+	x     node
+	field string
+}
+
+func (n selectNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok, err := lookup(x, n.field)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: no such field %q", ErrEval, n.field)
+	}
+	return v, nil
+}
+
+type indexNode struct {
+	x, index node
+}
+
+func (n indexNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch index := index.(type) {
+	case string:
+		v, ok, err := lookup(x, index)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: no such key %q", ErrEval, index)
+		}
+		return v, nil
+	case int64, uint64, float64:
+		list, ok := x.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: can't index %s with number", ErrEval, typeName(x))
+		}
+		i, ok := listIndex(index, len(list))
+		if !ok {
+			return nil, fmt.Errorf("%w: index %v out of range", ErrEval, index)
+		}
+		return normalize(list[i]), nil
+	default:
+		return nil, fmt.Errorf("%w: invalid index type %s", ErrEval, typeName(index))
+	}
+}
+
+type hasNode struct {
+	sel selectNode
+}
+
+func (n hasNode) eval(vars map[string]any) (any, error) {
+	x, err := n.sel.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok, err := lookup(x, n.sel.field)
+	return ok, err
+}
+
+type listNode struct {
+	elems []node
+}
+
+func (n listNode) eval(vars map[string]any) (any, error) {
+	list := make([]any, 0, len(n.elems))
+	for _, elem := range n.elems {
+		v, err := elem.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+type notNode struct {
+	x node
+}
+
+func (n notNode) eval(vars map[string]any) (any, error) {
+	x, err := evalBool(n.x, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !x, nil
+}
+
+type negNode struct {
+	x node
+}
+
+func (n negNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x := x.(type) {
+	case int64:
+		if x == math.MinInt64 {
+			return -float64(x), nil
+		}
+		return -x, nil
+	case uint64:
+		// the only uint64 with an int64 negation, e.g. literal -9223372036854775808
+		if x == 1<<63 {
+			return int64(math.MinInt64), nil
+		}
+		return -float64(x), nil
+	case float64:
+		return -x, nil
+	default:
+		return nil, fmt.Errorf("%w: can't negate %s", ErrEval, typeName(x))
+	}
+}
+
+type andNode struct {
+	x, y node
+}
+
+func (n andNode) eval(vars map[string]any) (any, error) {
+	x, err := evalBool(n.x, vars, "&&")
+	if err != nil || !x {
+		return false, err
+	}
+	return evalBool(n.y, vars, "&&")
+}
+
+type orNode struct {
+	x, y node
+}
+
+func (n orNode) eval(vars map[string]any) (any, error) {
+	x, err := evalBool(n.x, vars, "||")
+	if err != nil || x {
+		return x, err
+	}
+	return evalBool(n.y, vars, "||")
+}
+
+type compareNode struct {
+	op   string
+	x, y node
+}
+
+func (n compareNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(x, y), nil
+	case "!=":
+		return !equal(x, y), nil
+	case "in":
+		return contains(y, x)
+	}
+
+	c, err := compare(x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []any) (any, error)
+	args []node
+}
+
+func (n callNode) eval(vars map[string]any) (any, error) {
+	args := make([]any, 0, len(n.args))
+	for _, arg := range n.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return v, nil
+}
+
+type function struct {
+	// args is a number of arguments including receiver of a method.
+	args   int
+	method bool
+	call   func(args []any) (any, error)
+}
+
+var functions = map[string]function{
+	"size": {args: 1, call: func(args []any) (any, error) {
+		switch x := args[0].(type) {
+		case string:
+			return int64(len([]rune(x))), nil
+		case []any:
+			return int64(len(x)), nil
+		case map[string]any:
+			return int64(len(x)), nil
+		default:
+			return nil, fmt.Errorf("%w: size of %s", ErrEval, typeName(x))
+		}
+	}},
+	"startsWith": {args: 2, method: true, call: stringFunc(strings.HasPrefix)},
+	"endsWith":   {args: 2, method: true, call: stringFunc(strings.HasSuffix)},
+	"contains": {args: 2, method: true, call: func(args []any) (any, error) {
+		switch args[0].(type) {
+		case string:
+			return stringFunc(strings.Contains)(args)
+		case []any:
+			return contains(args[0], args[1])
+		default:
+			return nil, fmt.Errorf("%w: contains on %s", ErrEval, typeName(args[0]))
+		}
+	}},
+}
+
+func stringFunc(f func(s, arg string) bool) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		s, ok1 := args[0].(string)
+		arg, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%w: expected strings, got %s and %s", ErrEval, typeName(args[0]), typeName(args[1]))
+		}
+		return f(s, arg), nil
+	}
+}
+
+// evalBool evaluates operand of a logical operator, which must be bool.
+func evalBool(n node, vars map[string]any, op string) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: operand of %s is %s, not bool", ErrEval, op, typeName(v))
+	}
+	return b, nil
+}
+
+// lookup returns field of a map, the second result reports whether the field exists.
+func lookup(x any, field string) (any, bool, error) {
+	m, ok := x.(map[string]any)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: can't select field %q of %s", ErrEval, field, typeName(x))
+	}
+
+	v, ok := m[field]
+	return normalize(v), ok, nil
+}
+
+// contains reports whether the list has the element or the map has the key.
+func contains(container, elem any) (bool, error) {
+	switch c := container.(type) {
+	case []any:
+		for _, v := range c {
+			if equal(normalize(v), elem) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := elem.(string)
+		if !ok {
+			return false, nil
+		}
+		_, ok = c[key]
+		return ok, nil
+	default:
+		return false, fmt.Errorf("%w: can't search in %s", ErrEval, typeName(container))
+	}
+}
+
+func equal(x, y any) bool {
+	xl, ok1 := x.([]any)
+	yl, ok2 := y.([]any)
+	if ok1 && ok2 {
+		if len(xl) != len(yl) {
+			return false
+		}
+		for i := range xl {
+			if !equal(normalize(xl[i]), normalize(yl[i])) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if c, ok := compareNumbers(x, y); ok {
+		// NaN is not equal to anything, though it is neither less nor greater
+		return c == 0 && !isNaN(x) && !isNaN(y)
+	}
+
+	if reflect.TypeOf(x) != reflect.TypeOf(y) {
+		return false
+	}
+	switch x.(type) {
+	case nil, bool, string:
+		return x == y
+	default:
+		return reflect.DeepEqual(x, y)
+	}
+}
+
+// compare orders numbers and strings.
+func compare(x, y any) (int, error) {
+	if c, ok := compareNumbers(x, y); ok {
+		return c, nil
+	}
+
+	if x, ok := x.(string); ok {
+		if y, ok := y.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: can't compare %s and %s", ErrEval, typeName(x), typeName(y))
+}
+
+// compareNumbers orders numbers, the second result is false if any of operands is not a number.
+// Integers are compared exactly, they are converted to float64 only when the other operand is a float.
+func compareNumbers(x, y any) (int, bool) {
+	switch x := x.(type) {
+	case int64:
+		switch y := y.(type) {
+		case int64:
+			return cmp.Compare(x, y), true
+		case uint64:
+			if x < 0 {
+				return -1, true
+			}
+			return cmp.Compare(uint64(x), y), true
+		case float64:
+			return compareFloats(float64(x), y), true
+		}
+	case uint64:
+		switch y := y.(type) {
+		case int64:
+			if y < 0 {
+				return 1, true
+			}
+			return cmp.Compare(x, uint64(y)), true
+		case uint64:
+			return cmp.Compare(x, y), true
+		case float64:
+			return compareFloats(float64(x), y), true
+		}
+	case float64:
+		switch y := y.(type) {
+		case int64:
+			return compareFloats(x, float64(y)), true
+		case uint64:
+			return compareFloats(x, float64(y)), true
+		case float64:
+			return compareFloats(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// compareFloats orders floats, NaN is neither less nor greater than anything.
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNaN(v any) bool {
+	f, ok := v.(float64)
+	return ok && math.IsNaN(f)
+}
+
+// listIndex converts a number into index of a list of the length, the second result is false if it is out of range.
+func listIndex(index any, length int) (int, bool) {
+	switch index := index.(type) {
+	case int64:
+		if index >= 0 && index < int64(length) {
+			return int(index), true
+		}
+	case uint64:
+		if index < uint64(length) {
+			return int(index), true
+		}
+	case float64:
+		i := int(index)
+		if float64(i) == index && i >= 0 && i < length {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// normalize converts attribute values into expression types: integers into int64 (uint64 if they don't fit),
+// other numbers into float64, slices into []any and maps with string keys into map[string]any.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int64, float64, []any, map[string]any:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		return normalizeUint(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []string:
+		list := make([]any, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return normalizeUint(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Array:
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = normalize(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+// normalizeUint keeps unsigned integers that fit into int64 as int64, so both kinds of integers rarely meet.
+func normalizeUint(v uint64) any {
+	if v <= math.MaxInt64 {
+		return int64(v)
+	}
+	return v
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64, uint64, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
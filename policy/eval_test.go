@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestEvalNumbers(t *testing.T) {
+	vars := map[string]any{
+		"subject": map[string]any{"id": int64(1<<53 + 1)},
+		"resource": map[string]any{
+			"owner_id": int64(1 << 53),
+			"big":      uint64(math.MaxUint64),
+			"json_id":  json.Number("9007199254740993"),
+			"ratio":    0.5,
+			"ids":      []int64{1<<53 + 1, 2},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "subject.id == resource.owner_id", want: false},
+		{expr: "subject.id > resource.owner_id", want: true},
+		{expr: "subject.id == 9007199254740993", want: true},
+		{expr: "subject.id == resource.json_id", want: true},
+		{expr: "subject.id in resource.ids", want: true},
+		{expr: "resource.big > subject.id", want: true},
+		{expr: "resource.big == 18446744073709551615", want: true},
+		{expr: "-1 < resource.big", want: true},
+		{expr: "resource.ratio < 1", want: true},
+		{expr: "resource.ratio == 0.5", want: true},
+		{expr: "1 == 1.0", want: true},
+		{expr: "-9223372036854775808 < 0", want: true},
+		{expr: "size(resource.ids) == 2", want: true},
+		{expr: "resource.ids[1] == 2", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := e.EvalBool(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
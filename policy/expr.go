@@ -0,0 +1,460 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidExpr = errors.New("invalid expression")
+	ErrEval        = errors.New("evaluate expression")
+)
+
+// variables are names available in expressions.
+var variables = map[string]struct{}{
+	varSubject:  {},
+	varResource: {},
+	varAction:   {},
+}
+
+const (
+	varSubject  string = "subject"
+	varResource string = "resource"
+	varAction   string = "action"
+)
+
+// Expr is a compiled CEL-style boolean expression over subject, resource and action, e.g.:
+//
+//	resource.owner_id == subject.id || resource.department == subject.claims.department
+//
+// Supported are literals (strings, numbers, true, false, null and lists), field selection (a.b) and indexing (a["b"]),
+// operators !, -, ==, !=, <, <=, >, >=, in, && and ||, functions size(x) and has(a.b)
+// and methods s.startsWith(p), s.endsWith(p) and x.contains(y) for strings and lists.
+// Expr is immutable and safe for concurrent use.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses the expression and checks that it uses only known variables.
+func Compile(src string) (*Expr, error) {
+	p := &parser{lex: lexer{src: src}}
+	p.next()
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression with the variables.
+func (e *Expr) Eval(vars map[string]any) (any, error) {
+	return e.root.eval(vars)
+}
+
+// EvalBool evaluates the expression, which must result in a bool.
+func (e *Expr) EvalBool(vars map[string]any) (bool, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: result is %s, not bool", ErrEval, typeName(v))
+	}
+	return b, nil
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+// operators are sorted so that longer operators are matched first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "-", "(", ")", "[", "]", ",", "."}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && strings.ContainsRune(" \t\r\n", rune(l.src[l.pos])) {
+		l.pos++
+	}
+
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, text: "end of expression", pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case isLetter(c):
+		for l.pos < len(l.src) && (isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}, nil
+	case isDigit(c):
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: l.src[start:l.pos], pos: start}, nil
+	case c == '"' || c == '\'':
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] != c {
+			if l.src[l.pos] == '\\' {
+				l.pos++
+			}
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("%w %q at %d: unterminated string", ErrInvalidExpr, l.src, start)
+		}
+		l.pos++
+		return token{kind: tokString, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+
+	return token{}, fmt.Errorf("%w %q at %d: unexpected %q", ErrInvalidExpr, l.src, start, c)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser is a recursive descent parser:
+//
+//	or      = and { "||" and }
+//	and     = compare { "&&" compare }
+//	compare = unary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) unary ]
+//	unary   = ( "!" | "-" ) unary | postfix
+//	postfix = primary { "." ident [ "(" args ")" ] | "[" or "]" }
+//	primary = literal | ident | ident "(" args ")" | "(" or ")" | "[" args "]"
+type parser struct {
+	lex lexer
+	tok token
+	err error
+}
+
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.tok, p.err = p.lex.next()
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("%w %q at %d: %s", ErrInvalidExpr, p.lex.src, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// isOp reports whether current token is the operator or keyword.
+func (p *parser) isOp(op string) bool {
+	return p.err == nil && (p.tok.kind == tokOp || p.tok.kind == tokIdent) && p.tok.text == op
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected %q, got %q", op, p.tok.text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("||") {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = orNode{x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("&&") {
+		p.next()
+		y, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		x = andNode{x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseCompare() (node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.isOp(op) {
+			p.next()
+			y, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{op: op, x: x, y: y}, nil
+		}
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch {
+	case p.isOp("!"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	case p.isOp("-"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	default:
+		return p.parsePostfix()
+	}
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			if p.err != nil || p.tok.kind != tokIdent {
+				return nil, p.errorf("expected field name, got %q", p.tok.text)
+			}
+			name := p.tok.text
+			p.next()
+
+			if !p.isOp("(") {
+				x = selectNode{x: x, field: name}
+				continue
+			}
+
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			if x, err = p.newCall(name, append([]node{x}, args...), true); err != nil {
+				return nil, err
+			}
+		case p.isOp("["):
+			p.next()
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = indexNode{x: x, index: index}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		p.next()
+		v, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q at %d: number %q", ErrInvalidExpr, p.lex.src, tok.pos, tok.text)
+		}
+		return literalNode{v: v}, nil
+	case tokString:
+		p.next()
+		v, err := unquote(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q at %d: string %s", ErrInvalidExpr, p.lex.src, tok.pos, tok.text)
+		}
+		return literalNode{v: v}, nil
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return literalNode{v: true}, nil
+		case "false":
+			return literalNode{v: false}, nil
+		case "null":
+			return literalNode{v: nil}, nil
+		}
+
+		if p.isOp("(") {
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			return p.newCall(tok.text, args, false)
+		}
+
+		if _, ok := variables[tok.text]; !ok {
+			return nil, fmt.Errorf("%w %q at %d: unknown variable %q", ErrInvalidExpr, p.lex.src, tok.pos, tok.text)
+		}
+		return identNode{name: tok.text}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			p.next()
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			elems, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return listNode{elems: elems}, nil
+		}
+	}
+
+	return nil, p.errorf("unexpected %q", tok.text)
+}
+
+// parseArgs parses comma separated expressions between current opening token and the closing one.
+func (p *parser) parseArgs(closing string) ([]node, error) {
+	p.next()
+
+	var args []node
+	for !p.isOp(closing) {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+
+	return args, p.err
+}
+
+// parseNumber parses integer literals into int64 (uint64 if they don't fit) and the others into float64.
+func parseNumber(text string) (any, error) {
+	if strings.Contains(text, ".") {
+		return strconv.ParseFloat(text, 64)
+	}
+	if v, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return v, nil
+	}
+	return strconv.ParseUint(text, 10, 64)
+}
+
+// newCall checks the function and number of its arguments, receiver of a method is the first argument.
+func (p *parser) newCall(name string, args []node, method bool) (node, error) {
+	if name == "has" && !method {
+		if len(args) != 1 {
+			return nil, p.errorf("has() takes 1 argument")
+		}
+		sel, ok := args[0].(selectNode)
+		if !ok {
+			return nil, p.errorf("has() argument must be a field selection")
+		}
+		return hasNode{sel: sel}, nil
+	}
+
+	fn, ok := functions[name]
+	if !ok || fn.method != method {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	if len(args) != fn.args {
+		return nil, p.errorf("%s() takes %d argument(s)", name, fn.args-btoi(method))
+	}
+
+	return callNode{name: name, fn: fn.call, args: args}, nil
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// unquote converts single or double quoted string literal into string.
+func unquote(s string) (string, error) {
+	if s[0] == '\'' {
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
@@ -0,0 +1,204 @@
+// Package policy provides attribute-based access control: declarative rules with CEL-style conditions
+// over subject, resource and action, evaluated into allow or deny decision with reasons.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid rule")
+)
+
+// Effect is an effect of the rule that applies to the request.
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Rule is a declarative access rule, e.g.:
+//
+//	policy.Rule{
+//		Name:      "edit-own-or-department",
+//		Effect:    policy.Allow,
+//		Actions:   []string{"documents:edit"},
+//		Condition: `resource.owner_id == subject.id || resource.department == subject.claims.department`,
+//	}
+type Rule struct {
+	// Name identifies the rule in decision reasons.
+	Name   string `yaml:"name"`
+	Effect Effect `yaml:"effect"`
+	// Actions are action patterns the rule applies to, e.g. "documents:*", empty list matches all actions.
+	Actions []string `yaml:"actions"`
+	// Condition is a boolean expression, see Expr. Empty condition is always true.
+	Condition string `yaml:"condition"`
+}
+
+// Request is an access request, Subject is usually created from model.AuthMeta with Subject.
+type Request struct {
+	Subject  map[string]any
+	Resource map[string]any
+	Action   string
+}
+
+// Decision is a result of policy evaluation.
+type Decision struct {
+	Allowed bool
+	// Reasons explain the decision: which rules allowed or denied the request and which conditions failed.
+	Reasons []string
+}
+
+// Policy is a set of rules combined with deny-overrides algorithm: the request is allowed
+// if at least one allow rule applies and no deny rule applies, otherwise it is denied.
+// Conditions that fail to evaluate, e.g. because of missing attribute, fail safe:
+// allow rule doesn't apply, while deny rule does. Policy is safe for concurrent use.
+type Policy struct {
+	rules []rule
+}
+
+type rule struct {
+	Rule
+	cond *Expr
+}
+
+// New compiles the rules into Policy.
+func New(rules ...Rule) (*Policy, error) {
+	p := &Policy{
+		rules: make([]rule, 0, len(rules)),
+	}
+
+	for i, r := range rules {
+		if r.Name == "" {
+			r.Name = "#" + strconv.Itoa(i)
+		}
+		if r.Effect != Allow && r.Effect != Deny {
+			return nil, fmt.Errorf("%w %q: effect %q", ErrInvalidRule, r.Name, r.Effect)
+		}
+		for _, action := range r.Actions {
+			if _, err := path.Match(action, ""); err != nil {
+				return nil, fmt.Errorf("%w %q: action %q: %w", ErrInvalidRule, r.Name, action, err)
+			}
+		}
+
+		compiled := rule{Rule: r}
+		if r.Condition != "" {
+			cond, err := Compile(r.Condition)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidRule, r.Name, err)
+			}
+			compiled.cond = cond
+		}
+		p.rules = append(p.rules, compiled)
+	}
+
+	return p, nil
+}
+
+// Evaluate decides whether the request is allowed.
+func (p *Policy) Evaluate(req Request) Decision {
+	vars := map[string]any{
+		varSubject:  req.Subject,
+		varResource: req.Resource,
+		varAction:   req.Action,
+	}
+
+	var allowed, denied, failed []string
+	for _, r := range p.rules {
+		if !r.matchAction(req.Action) {
+			continue
+		}
+
+		applies := true
+		if r.cond != nil {
+			ok, err := r.cond.EvalBool(vars)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("rule %q: %v", r.Name, err))
+				// fail safe: broken deny rule denies, broken allow rule doesn't allow
+				ok = r.Effect == Deny
+			}
+			applies = ok
+		}
+		if !applies {
+			continue
+		}
+
+		if r.Effect == Deny {
+			denied = append(denied, fmt.Sprintf("denied by rule %q", r.Name))
+		} else {
+			allowed = append(allowed, fmt.Sprintf("allowed by rule %q", r.Name))
+		}
+	}
+
+	switch {
+	case len(denied) > 0:
+		return Decision{Allowed: false, Reasons: append(denied, failed...)}
+	case len(allowed) > 0:
+		return Decision{Allowed: true, Reasons: append(allowed, failed...)}
+	default:
+		return Decision{
+			Allowed: false,
+			Reasons: append([]string{fmt.Sprintf("no rule allows action %q", req.Action)}, failed...),
+		}
+	}
+}
+
+// matchAction reports whether the rule applies to the action.
+func (r *rule) matchAction(action string) bool {
+	if len(r.Actions) == 0 {
+		return true
+	}
+	for _, pattern := range r.Actions {
+		if ok, _ := path.Match(pattern, action); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject converts AuthMeta into subject attributes: id, session_id, roles and permissions (lists of names),
+// org_id with org_roles and org_permissions of the active organization
+// and claims with custom claims of the token.
+func Subject(meta model.AuthMeta) map[string]any {
+	roles := make([]any, 0, len(meta.Roles))
+	for _, role := range meta.Roles {
+		roles = append(roles, role.Name)
+	}
+
+	permissions := make([]any, 0, len(meta.Permissions))
+	for _, permission := range meta.Permissions {
+		permissions = append(permissions, permission)
+	}
+
+	orgRoles := make([]any, 0, len(meta.OrgRoles))
+	for _, role := range meta.OrgRoles {
+		orgRoles = append(orgRoles, role.Name)
+	}
+
+	orgPermissions := make([]any, 0, len(meta.OrgPermissions))
+	for _, permission := range meta.OrgPermissions {
+		orgPermissions = append(orgPermissions, permission)
+	}
+
+	claims := make(map[string]any, len(meta.Extra))
+	for name, value := range meta.Extra {
+		claims[name] = value
+	}
+
+	return map[string]any{
+		"id":              meta.UserID,
+		"session_id":      meta.SessionID,
+		"roles":           roles,
+		"permissions":     permissions,
+		"org_id":          meta.OrgID,
+		"org_roles":       orgRoles,
+		"org_permissions": orgPermissions,
+		"claims":          claims,
+	}
+}
@@ -0,0 +1,101 @@
+package rebac
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidNamespace = errors.New("invalid namespace config")
+	ErrUnknownNamespace = errors.New("unknown namespace")
+	ErrUnknownRelation  = errors.New("unknown relation")
+)
+
+// Namespace configures relations of objects of one type, e.g.:
+//
+//	rebac.Namespace{
+//		Name: "document",
+//		Relations: map[string]rebac.Relation{
+//			"parent": {},
+//			"owner":  {},
+//			"editor": {ComputedUsersets: []string{"owner"}},
+//			"viewer": {
+//				ComputedUsersets: []string{"editor"},
+//				TupleToUsersets:  []rebac.TupleToUserset{{Tupleset: "parent", ComputedUserset: "viewer"}},
+//			},
+//		},
+//	}
+//
+// Here owners are editors, editors are viewers and viewers of the parent folder are viewers of the document.
+type Namespace struct {
+	Name      string              `yaml:"name"`
+	Relations map[string]Relation `yaml:"relations"`
+}
+
+// Relation is a union of subjects directly written for the relation and the ones computed by rewrite rules.
+type Relation struct {
+	// ComputedUsersets are relations of the same object included into this relation.
+	ComputedUsersets []string `yaml:"computed_usersets"`
+	// TupleToUsersets include relations of objects related by tupleset relation.
+	TupleToUsersets []TupleToUserset `yaml:"tuple_to_usersets"`
+}
+
+// TupleToUserset includes subjects having ComputedUserset relation to every object,
+// which is a subject of Tupleset relation, e.g. viewers of parent folders of the document.
+type TupleToUserset struct {
+	Tupleset        string `yaml:"tupleset"`
+	ComputedUserset string `yaml:"computed_userset"`
+}
+
+// schema is a validated set of namespaces.
+type schema map[string]Namespace
+
+func newSchema(namespaces []Namespace) (schema, error) {
+	s := make(schema, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Name == "" {
+			return nil, fmt.Errorf("%w: empty namespace name", ErrInvalidNamespace)
+		}
+		if _, ok := s[ns.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate namespace %q", ErrInvalidNamespace, ns.Name)
+		}
+		s[ns.Name] = ns
+	}
+
+	for _, ns := range s {
+		for name, rel := range ns.Relations {
+			for _, computed := range rel.ComputedUsersets {
+				if _, ok := ns.Relations[computed]; !ok {
+					return nil, fmt.Errorf("%w: %s#%s: computed userset %q is not defined",
+						ErrInvalidNamespace, ns.Name, name, computed)
+				}
+			}
+			for _, ttu := range rel.TupleToUsersets {
+				if _, ok := ns.Relations[ttu.Tupleset]; !ok {
+					return nil, fmt.Errorf("%w: %s#%s: tupleset %q is not defined",
+						ErrInvalidNamespace, ns.Name, name, ttu.Tupleset)
+				}
+				if ttu.ComputedUserset == "" {
+					return nil, fmt.Errorf("%w: %s#%s: empty computed userset of tupleset %q",
+						ErrInvalidNamespace, ns.Name, name, ttu.Tupleset)
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// relation returns config of the relation.
+func (s schema) relation(namespace, relation string) (Relation, error) {
+	ns, ok := s[namespace]
+	if !ok {
+		return Relation{}, fmt.Errorf("%w %q", ErrUnknownNamespace, namespace)
+	}
+
+	rel, ok := ns.Relations[relation]
+	if !ok {
+		return Relation{}, fmt.Errorf("%w %s#%s", ErrUnknownRelation, namespace, relation)
+	}
+	return rel, nil
+}
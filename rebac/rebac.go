@@ -0,0 +1,370 @@
+// Package rebac provides relationship-based access control in the style of Zanzibar: access is derived
+// from relation tuples like "document:42#viewer@user:7" and rewrite rules configured per namespace.
+package rebac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+)
+
+const (
+	// maxCheckDepth limits nesting of usersets and rewrites evaluated by Check.
+	maxCheckDepth int = 32
+	// tokenPrefix is a prefix of consistency token.
+	tokenPrefix string = "r"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid consistency token")
+	ErrStaleRevision = errors.New("tuple store is behind consistency token")
+	ErrMaxDepth      = errors.New("max check depth exceeded")
+)
+
+// Token is an opaque consistency token, which identifies a revision of tuples.
+// Write returns a token of the change, which can be stored alongside the content
+// and passed to Check and ListObjects to evaluate them at a revision at least as fresh as the change.
+type Token string
+
+func newToken(revision int64) Token {
+	return Token(tokenPrefix + strconv.FormatInt(revision, 36))
+}
+
+func (t Token) revision() (int64, error) {
+	s, ok := strings.CutPrefix(string(t), tokenPrefix)
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrInvalidToken, t)
+	}
+
+	revision, err := strconv.ParseInt(s, 36, 64)
+	if err != nil || revision < 0 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidToken, t)
+	}
+	return revision, nil
+}
+
+// Engine writes relation tuples and evaluates access over them.
+type Engine struct {
+	tuples store.TupleStore
+	schema schema
+}
+
+// New creates Engine over the store with namespaces, every tuple must use configured namespaces and relations.
+func New(tuples store.TupleStore, namespaces ...Namespace) (*Engine, error) {
+	s, err := newSchema(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tuples.ApplyMigrations(); err != nil {
+		return nil, fmt.Errorf("tuple store: %w", err)
+	}
+
+	return &Engine{
+		tuples: tuples,
+		schema: s,
+	}, nil
+}
+
+// Write atomically inserts and deletes tuples and returns consistency token of the change.
+func (e *Engine) Write(ctx context.Context, inserts, deletes []model.RelationTuple) (Token, error) {
+	for _, t := range slices.Concat(inserts, deletes) {
+		if err := e.validate(t); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, err := e.tuples.StartTx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("tuple store transaction: %w", err)
+	}
+	defer func() {
+		if err := e.tuples.RollbackTx(ctx); err != nil {
+			panic(err)
+		}
+	}()
+
+	revision, err := e.tuples.Write(ctx, inserts, deletes)
+	if err != nil {
+		return "", fmt.Errorf("write tuples: %w", err)
+	}
+
+	if err := e.tuples.CommitTx(ctx); err != nil {
+		return "", fmt.Errorf("commit tuples: %w", err)
+	}
+
+	return newToken(revision), nil
+}
+
+// validate checks that the tuple uses configured relations.
+func (e *Engine) validate(t model.RelationTuple) error {
+	if _, err := e.schema.relation(t.Object.Namespace, t.Relation); err != nil {
+		return fmt.Errorf("tuple %s: %w", t, err)
+	}
+	if t.Subject.Relation != "" {
+		if _, err := e.schema.relation(t.Subject.Namespace, t.Subject.Relation); err != nil {
+			return fmt.Errorf("tuple %s: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// snapshot returns the latest revision, which is at least as fresh as the token. Empty token means the latest revision.
+func (e *Engine) snapshot(ctx context.Context, token Token) (int64, error) {
+	latest, err := e.tuples.Revision(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("latest revision: %w", err)
+	}
+
+	if token == "" {
+		return latest, nil
+	}
+
+	revision, err := token.revision()
+	if err != nil {
+		return 0, err
+	}
+	if revision > latest {
+		return 0, ErrStaleRevision
+	}
+
+	return latest, nil
+}
+
+// Check reports whether the subject has the relation to the object, directly or through usersets and rewrite rules.
+// All tuples are read at the same revision, which is returned as a token.
+func (e *Engine) Check(
+	ctx context.Context,
+	object model.Object,
+	relation string,
+	subject model.Subject,
+	token Token,
+) (bool, Token, error) {
+	revision, err := e.snapshot(ctx, token)
+	if err != nil {
+		return false, "", err
+	}
+
+	c := &checker{
+		ctx:      ctx,
+		e:        e,
+		revision: revision,
+		subject:  subject,
+		path:     make(map[model.Subject]struct{}),
+	}
+
+	ok, err := c.check(model.Subject{Object: object, Relation: relation}, 0)
+	if err != nil {
+		return false, "", err
+	}
+
+	return ok, newToken(revision), nil
+}
+
+// checker evaluates a single Check request.
+type checker struct {
+	ctx      context.Context
+	e        *Engine
+	revision int64
+	subject  model.Subject
+	// path holds usersets being evaluated to stop on cycles.
+	path map[model.Subject]struct{}
+}
+
+// check reports whether the userset contains the subject.
+func (c *checker) check(userset model.Subject, depth int) (bool, error) {
+	if depth > maxCheckDepth {
+		return false, ErrMaxDepth
+	}
+	if userset == c.subject {
+		return true, nil
+	}
+
+	rel, err := c.e.schema.relation(userset.Namespace, userset.Relation)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := c.path[userset]; ok {
+		return false, nil
+	}
+	c.path[userset] = struct{}{}
+	defer delete(c.path, userset)
+
+	subjects, err := c.e.tuples.ReadSubjects(c.ctx, userset.Object, userset.Relation, c.revision)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", userset, err)
+	}
+	if slices.Contains(subjects, c.subject) {
+		return true, nil
+	}
+
+	for _, s := range subjects {
+		if s.Relation == "" {
+			continue
+		}
+		if ok, err := c.check(s, depth+1); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	for _, computed := range rel.ComputedUsersets {
+		if ok, err := c.check(model.Subject{Object: userset.Object, Relation: computed}, depth+1); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	for _, ttu := range rel.TupleToUsersets {
+		related, err := c.e.tuples.ReadSubjects(c.ctx, userset.Object, ttu.Tupleset, c.revision)
+		if err != nil {
+			return false, fmt.Errorf("read %s#%s: %w", userset.Object, ttu.Tupleset, err)
+		}
+
+		for _, r := range related {
+			if _, err := c.e.schema.relation(r.Namespace, ttu.ComputedUserset); err != nil {
+				continue
+			}
+			next := model.Subject{Object: r.Object, Relation: ttu.ComputedUserset}
+			if ok, err := c.check(next, depth+1); err != nil || ok {
+				return ok, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// ListObjects returns objects of the namespace to which the subject has the relation.
+// Usersets containing the subject are expanded in reverse: from tuples with the subject to usersets
+// including them by rewrite rules, so cost depends on the number of subject relations, not on the number of objects.
+func (e *Engine) ListObjects(
+	ctx context.Context,
+	namespace, relation string,
+	subject model.Subject,
+	token Token,
+) ([]model.Object, Token, error) {
+	if _, err := e.schema.relation(namespace, relation); err != nil {
+		return nil, "", err
+	}
+
+	revision, err := e.snapshot(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	l := &lister{
+		ctx:      ctx,
+		e:        e,
+		revision: revision,
+		visited:  make(map[model.Subject]struct{}),
+		related:  make(map[model.Object][]model.RelationTuple),
+	}
+
+	if subject.Relation == "" {
+		tuples, err := l.readRelated(subject.Object)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, t := range tuples {
+			l.add(model.Subject{Object: t.Object, Relation: t.Relation})
+		}
+	} else {
+		l.add(subject)
+	}
+
+	var objects []model.Object
+	for len(l.pending) > 0 {
+		userset := l.pending[len(l.pending)-1]
+		l.pending = l.pending[:len(l.pending)-1]
+
+		if userset.Namespace == namespace && userset.Relation == relation {
+			objects = append(objects, userset.Object)
+		}
+
+		if err := l.expand(userset); err != nil {
+			return nil, "", err
+		}
+	}
+
+	slices.SortFunc(objects, func(a, b model.Object) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return objects, newToken(revision), nil
+}
+
+// lister evaluates a single ListObjects request.
+type lister struct {
+	ctx      context.Context
+	e        *Engine
+	revision int64
+	// visited are usersets known to contain the subject.
+	visited map[model.Subject]struct{}
+	// pending are visited usersets, which are not expanded yet.
+	pending []model.Subject
+	// related caches tuples with the object as a plain subject.
+	related map[model.Object][]model.RelationTuple
+}
+
+func (l *lister) add(userset model.Subject) {
+	if _, ok := l.visited[userset]; ok {
+		return
+	}
+	l.visited[userset] = struct{}{}
+	l.pending = append(l.pending, userset)
+}
+
+func (l *lister) readRelated(object model.Object) ([]model.RelationTuple, error) {
+	if tuples, ok := l.related[object]; ok {
+		return tuples, nil
+	}
+
+	tuples, err := l.e.tuples.ReadBySubject(l.ctx, model.Subject{Object: object}, l.revision)
+	if err != nil {
+		return nil, fmt.Errorf("read tuples of %s: %w", object, err)
+	}
+	l.related[object] = tuples
+	return tuples, nil
+}
+
+// expand adds usersets that include the userset.
+func (l *lister) expand(userset model.Subject) error {
+	// tuples with the userset as a subject
+	tuples, err := l.e.tuples.ReadBySubject(l.ctx, userset, l.revision)
+	if err != nil {
+		return fmt.Errorf("read tuples of %s: %w", userset, err)
+	}
+	for _, t := range tuples {
+		l.add(model.Subject{Object: t.Object, Relation: t.Relation})
+	}
+
+	// relations of the same object computed from the userset relation
+	ns := l.e.schema[userset.Namespace]
+	for name, rel := range ns.Relations {
+		if slices.Contains(rel.ComputedUsersets, userset.Relation) {
+			l.add(model.Subject{Object: userset.Object, Relation: name})
+		}
+	}
+
+	// relations of objects, which point to the userset object with tupleset relation
+	related, err := l.readRelated(userset.Object)
+	if err != nil {
+		return err
+	}
+	for _, t := range related {
+		for name, rel := range l.e.schema[t.Object.Namespace].Relations {
+			for _, ttu := range rel.TupleToUsersets {
+				if ttu.Tupleset == t.Relation && ttu.ComputedUserset == userset.Relation {
+					l.add(model.Subject{Object: t.Object, Relation: name})
+				}
+			}
+		}
+	}
+
+	return nil
+}
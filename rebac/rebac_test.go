@@ -0,0 +1,339 @@
+package rebac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store/memory"
+)
+
+var testNamespaces = []Namespace{
+	{
+		Name:      "group",
+		Relations: map[string]Relation{"member": {}},
+	},
+	{
+		Name: "folder",
+		Relations: map[string]Relation{
+			"parent": {},
+			"owner":  {},
+			"viewer": {
+				ComputedUsersets: []string{"owner"},
+				TupleToUsersets:  []TupleToUserset{{Tupleset: "parent", ComputedUserset: "viewer"}},
+			},
+		},
+	},
+	{
+		Name: "document",
+		Relations: map[string]Relation{
+			"parent": {},
+			"owner":  {},
+			"editor": {ComputedUsersets: []string{"owner"}},
+			"viewer": {
+				ComputedUsersets: []string{"editor"},
+				TupleToUsersets:  []TupleToUserset{{Tupleset: "parent", ComputedUserset: "viewer"}},
+			},
+		},
+	},
+}
+
+func newTestEngine(t *testing.T, tuples ...string) (*Engine, Token) {
+	t.Helper()
+
+	e, err := New(memory.NewTupleStore(memory.NewDB()), testNamespaces...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inserts := make([]model.RelationTuple, len(tuples))
+	for i, s := range tuples {
+		inserts[i], err = model.ParseRelationTuple(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	token, err := e.Write(context.Background(), inserts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e, token
+}
+
+func mustObject(t *testing.T, s string) model.Object {
+	t.Helper()
+
+	o, err := model.ParseObject(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+func mustSubject(t *testing.T, s string) model.Subject {
+	t.Helper()
+
+	sub, err := model.ParseSubject(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+var testTuples = []string{
+	"group:eng#member@user:1",
+	"group:all#member@group:eng#member",
+	"document:d1#owner@user:2",
+	"document:d1#viewer@group:eng#member",
+	"document:d4#viewer@group:all#member",
+	"folder:f1#viewer@user:3",
+	"folder:f2#parent@folder:f1",
+	"document:d2#parent@folder:f1",
+	"document:d3#parent@folder:f2",
+	"folder:f3#owner@user:4",
+	"document:d5#parent@folder:f3",
+}
+
+func TestCheck(t *testing.T) {
+	e, _ := newTestEngine(t, testTuples...)
+
+	tests := []struct {
+		name     string
+		object   string
+		relation string
+		subject  string
+		want     bool
+	}{
+		{name: "direct", object: "document:d1", relation: "owner", subject: "user:2", want: true},
+		{name: "computed userset", object: "document:d1", relation: "editor", subject: "user:2", want: true},
+		{name: "computed userset chain", object: "document:d1", relation: "viewer", subject: "user:2", want: true},
+		{name: "userset tuple", object: "document:d1", relation: "viewer", subject: "user:1", want: true},
+		{name: "nested userset tuple", object: "document:d4", relation: "viewer", subject: "user:1", want: true},
+		{name: "userset subject", object: "document:d1", relation: "viewer", subject: "group:eng#member", want: true},
+		{name: "tuple to userset", object: "document:d2", relation: "viewer", subject: "user:3", want: true},
+		{name: "tuple to userset chain", object: "document:d3", relation: "viewer", subject: "user:3", want: true},
+		{name: "tuple to computed userset", object: "document:d5", relation: "viewer", subject: "user:4", want: true},
+		{name: "computed userset is one-way", object: "document:d1", relation: "editor", subject: "user:1"},
+		{name: "tuple to userset is one-way", object: "folder:f1", relation: "viewer", subject: "document:d2"},
+		{name: "unrelated object", object: "document:d2", relation: "viewer", subject: "user:2"},
+		{name: "tupleset is not a viewer", object: "document:d5", relation: "owner", subject: "user:4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, _, err := e.Check(context.Background(), mustObject(t, tt.object), tt.relation, mustSubject(t, tt.subject), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Errorf("Check(%s#%s@%s) = %v, want %v", tt.object, tt.relation, tt.subject, ok, tt.want)
+			}
+		})
+	}
+
+	_, _, err := e.Check(context.Background(), mustObject(t, "document:d1"), "reader", mustSubject(t, "user:1"), "")
+	if !errors.Is(err, ErrUnknownRelation) {
+		t.Errorf("unknown relation: got %v, want ErrUnknownRelation", err)
+	}
+}
+
+func TestListObjects(t *testing.T) {
+	e, _ := newTestEngine(t, testTuples...)
+
+	tests := []struct {
+		namespace string
+		relation  string
+		subject   string
+		want      []string
+	}{
+		{namespace: "document", relation: "owner", subject: "user:2", want: []string{"d1"}},
+		{namespace: "document", relation: "viewer", subject: "user:2", want: []string{"d1"}},
+		{namespace: "document", relation: "viewer", subject: "user:1", want: []string{"d1", "d4"}},
+		{namespace: "document", relation: "viewer", subject: "group:eng#member", want: []string{"d1", "d4"}},
+		{namespace: "document", relation: "viewer", subject: "user:3", want: []string{"d2", "d3"}},
+		{namespace: "document", relation: "viewer", subject: "user:4", want: []string{"d5"}},
+		{namespace: "folder", relation: "viewer", subject: "user:3", want: []string{"f1", "f2"}},
+		{namespace: "document", relation: "editor", subject: "user:1"},
+		{namespace: "group", relation: "member", subject: "user:1", want: []string{"all", "eng"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s#%s@%s", tt.namespace, tt.relation, tt.subject), func(t *testing.T) {
+			objects, _, err := e.ListObjects(context.Background(), tt.namespace, tt.relation, mustSubject(t, tt.subject), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, o := range objects {
+				if o.Namespace != tt.namespace {
+					t.Errorf("object %s is not in namespace %s", o, tt.namespace)
+				}
+				ids = append(ids, o.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := e.ListObjects(context.Background(), "page", "viewer", mustSubject(t, "user:1"), ""); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("unknown namespace: got %v, want ErrUnknownNamespace", err)
+	}
+}
+
+func TestCycleAndDepth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cycle", func(t *testing.T) {
+		e, _ := newTestEngine(t,
+			"group:a#member@group:b#member",
+			"group:b#member@group:a#member",
+			"group:b#member@user:1",
+		)
+
+		for _, tt := range []struct {
+			subject string
+			want    bool
+		}{
+			{subject: "user:1", want: true},
+			{subject: "user:2", want: false},
+		} {
+			ok, _, err := e.Check(ctx, mustObject(t, "group:a"), "member", mustSubject(t, tt.subject), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Errorf("Check(group:a#member@%s) = %v, want %v", tt.subject, ok, tt.want)
+			}
+		}
+
+		objects, _, err := e.ListObjects(ctx, "group", "member", mustSubject(t, "user:1"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []model.Object{{Namespace: "group", ID: "a"}, {Namespace: "group", ID: "b"}}; !reflect.DeepEqual(objects, want) {
+			t.Errorf("got %v, want %v", objects, want)
+		}
+	})
+
+	// chain returns tuples nesting n groups, so the user is found at depth n
+	chain := func(n int) []string {
+		tuples := make([]string, 0, n+1)
+		for i := range n {
+			tuples = append(tuples, fmt.Sprintf("group:g%d#member@group:g%d#member", i, i+1))
+		}
+		return append(tuples, fmt.Sprintf("group:g%d#member@user:1", n))
+	}
+
+	t.Run("within depth", func(t *testing.T) {
+		e, _ := newTestEngine(t, chain(maxCheckDepth)...)
+
+		ok, _, err := e.Check(ctx, mustObject(t, "group:g0"), "member", mustSubject(t, "user:1"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("user is not a member at max depth")
+		}
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		e, _ := newTestEngine(t, chain(maxCheckDepth+2)...)
+
+		if _, _, err := e.Check(ctx, mustObject(t, "group:g0"), "member", mustSubject(t, "user:1"), ""); !errors.Is(err, ErrMaxDepth) {
+			t.Errorf("got %v, want ErrMaxDepth", err)
+		}
+
+		objects, _, err := e.ListObjects(ctx, "group", "member", mustSubject(t, "user:1"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objects) != maxCheckDepth+3 {
+			t.Errorf("got %d objects, want %d", len(objects), maxCheckDepth+3)
+		}
+	})
+}
+
+func TestConsistencyToken(t *testing.T) {
+	ctx := context.Background()
+	e, written := newTestEngine(t, "document:d1#viewer@user:1")
+
+	document := mustObject(t, "document:d1")
+	user := mustSubject(t, "user:1")
+
+	ok, token, err := e.Check(ctx, document, "viewer", user, written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || token != written {
+		t.Fatalf("Check at write token: got %v, %q, want true, %q", ok, token, written)
+	}
+
+	tuple, err := model.ParseRelationTuple("document:d1#viewer@user:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := e.Write(ctx, nil, []model.RelationTuple{tuple})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an older token is satisfied by the latest revision, so the delete is visible
+	for _, token := range []Token{"", written, deleted} {
+		ok, got, err := e.Check(ctx, document, "viewer", user, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok || got != deleted {
+			t.Errorf("Check at %q: got %v, %q, want false, %q", token, ok, got, deleted)
+		}
+
+		objects, got, err := e.ListObjects(ctx, "document", "viewer", user, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objects) != 0 || got != deleted {
+			t.Errorf("ListObjects at %q: got %v, %q, want none, %q", token, objects, got, deleted)
+		}
+	}
+
+	if _, _, err := e.Check(ctx, document, "viewer", user, newToken(100)); !errors.Is(err, ErrStaleRevision) {
+		t.Errorf("token ahead of store: got %v, want ErrStaleRevision", err)
+	}
+	if _, _, err := e.ListObjects(ctx, "document", "viewer", user, newToken(100)); !errors.Is(err, ErrStaleRevision) {
+		t.Errorf("token ahead of store: got %v, want ErrStaleRevision", err)
+	}
+	for _, token := range []Token{"42", "r", "r-1", "r!"} {
+		if _, _, err := e.Check(ctx, document, "viewer", user, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("token %q: got %v, want ErrInvalidToken", token, err)
+		}
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	e, _ := newTestEngine(t)
+
+	for _, s := range []string{"document:d1#reader@user:1", "page:p1#viewer@user:1", "document:d1#viewer@group:eng#admin"} {
+		tuple, err := model.ParseRelationTuple(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.Write(context.Background(), []model.RelationTuple{tuple}, nil); err == nil {
+			t.Errorf("tuple %s is written", s)
+		}
+	}
+
+	for _, namespaces := range [][]Namespace{
+		{{Name: ""}},
+		{{Name: "group"}, {Name: "group"}},
+		{{Name: "group", Relations: map[string]Relation{"member": {ComputedUsersets: []string{"owner"}}}}},
+		{{Name: "group", Relations: map[string]Relation{"member": {TupleToUsersets: []TupleToUserset{{Tupleset: "parent"}}}}}},
+	} {
+		if _, err := New(memory.NewTupleStore(memory.NewDB()), namespaces...); !errors.Is(err, ErrInvalidNamespace) {
+			t.Errorf("namespaces %+v: got %v, want ErrInvalidNamespace", namespaces, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+const (
+	// defaultRefreshExpire is used when JwtConfig.RefreshExpire is not set (30 days).
+	defaultRefreshExpire int = 24 * 30
+	// refreshTokenSize is a number of random bytes in refresh token.
+	refreshTokenSize int = 32
+)
+
+var (
+	ErrRefreshDisabled     = errors.New("refresh tokens are not enabled")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token is expired")
+	ErrRefreshTokenRevoked = errors.New("refresh token is revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// issueRefreshToken creates and persists a new refresh token in the family and returns its raw value.
+func (ctrl *controller) issueRefreshToken(ctx context.Context, meta model.AuthMeta, familyID string) (string, error) {
+	var err error
+
+	if familyID == "" {
+		familyID, err = generateToken(16)
+		if err != nil {
+			return "", fmt.Errorf("generate token family: %w", err)
+		}
+	}
+
+	rawToken, err := generateToken(refreshTokenSize)
+	if err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	expire := ctrl.cfg.Jwt.RefreshExpire
+	if expire <= 0 {
+		expire = defaultRefreshExpire
+	}
+
+	token := model.RefreshTokenDao{
+		UserID:    meta.UserID,
+		SessionID: meta.SessionID,
+		OrgID:     meta.OrgID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: time.Now().Add(time.Hour * time.Duration(expire)),
+	}
+	if _, err := ctrl.refresh.InsertOne(ctx, token); err != nil {
+		return "", fmt.Errorf("insert refresh token: %w", err)
+	}
+
+	return rawToken, nil
+}
+
+func (ctrl *controller) Refresh(ctx context.Context, refreshToken string) (model.AuthResp, error) {
+	if ctrl.refresh == nil {
+		return model.AuthResp{}, ErrRefreshDisabled
+	}
+
+	return ctrl.rotateRefreshToken(ctx, refreshToken, func(token model.RefreshTokenDao) (int64, error) {
+		return token.OrgID, nil
+	})
+}
+
+// rotateRefreshToken marks the refresh token used and issues new tokens within the same family,
+// orgID validates the token and returns the active organization of the new tokens.
+func (ctrl *controller) rotateRefreshToken(
+	ctx context.Context,
+	refreshToken string,
+	orgID func(token model.RefreshTokenDao) (int64, error),
+) (model.AuthResp, error) {
+	var resp model.AuthResp
+
+	token, err := ctrl.refresh.FindOneByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return resp, fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err)
+	}
+
+	switch {
+	case token.RevokedAt != nil:
+		return resp, ErrRefreshTokenRevoked
+	case token.UsedAt != nil:
+		return resp, ctrl.revokeFamily(ctx, token.FamilyID)
+	case time.Now().After(token.ExpiresAt):
+		return resp, ErrRefreshTokenExpired
+	}
+
+	org, err := orgID(token)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := ctrl.continueSession(ctx, token.SessionID); err != nil {
+		return resp, fmt.Errorf("continue session: %w", err)
+	}
+
+	txCtx, err := ctrl.refresh.StartTx(ctx)
+	if err != nil {
+		return resp, fmt.Errorf("refresh token store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.refresh.RollbackTx(txCtx); err != nil {
+			panic(fmt.Errorf("rollback refresh token store transaction: %w", err))
+		}
+	}()
+
+	unused, err := ctrl.refresh.MarkUsed(txCtx, token.ID)
+	if err != nil {
+		return resp, fmt.Errorf("mark refresh token used: %w", err)
+	}
+	if !unused {
+		// the token was used concurrently, so one of the requests is a replay
+		return resp, ctrl.revokeFamily(ctx, token.FamilyID)
+	}
+
+	meta, err := ctrl.authMeta(txCtx, token.UserID, org)
+	if err != nil {
+		return resp, err
+	}
+	meta.SessionID = token.SessionID
+
+	resp, err = ctrl.issueTokens(txCtx, meta, token.FamilyID)
+	if err != nil {
+		return resp, err
+	}
+
+	if err = ctrl.refresh.CommitTx(txCtx); err != nil {
+		return resp, fmt.Errorf("commit refresh token transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// revokeFamily revokes the whole refresh token family after a reuse was detected.
+func (ctrl *controller) revokeFamily(ctx context.Context, familyID string) error {
+	if err := ctrl.refresh.RevokeFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("%w: revoke token family: %w", ErrRefreshTokenReused, err)
+	}
+	return ErrRefreshTokenReused
+}
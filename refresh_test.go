@@ -0,0 +1,54 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	refresh := &refreshStoreStub{}
+	ctrl := newTestController(t, AuthConfig{}, WithRefreshTokenStore(refresh))
+
+	resp := registerUser(t, ctrl, "user")
+
+	rotated, err := ctrl.Refresh(ctx, resp.RefreshToken)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if rotated.RefreshToken == "" || rotated.RefreshToken == resp.RefreshToken {
+		t.Fatalf("refresh token is not rotated")
+	}
+	if rotated.Meta.UserID != resp.Meta.UserID {
+		t.Errorf("user id = %d, want %d", rotated.Meta.UserID, resp.Meta.UserID)
+	}
+	if refresh.tokens[0].FamilyID != refresh.tokens[1].FamilyID {
+		t.Errorf("rotated token starts a new family")
+	}
+
+	// replaying the used token revokes the whole family, including the rotated token
+	if _, err := ctrl.Refresh(ctx, resp.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replay: got %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := ctrl.Refresh(ctx, rotated.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("rotated token after replay: got %v, want ErrRefreshTokenRevoked", err)
+	}
+
+	login, err := ctrl.Login(ctx, model.UserLogin{Email: "user@example.com", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh.tokens[len(refresh.tokens)-1].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := ctrl.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Fatalf("expired: got %v, want ErrRefreshTokenExpired", err)
+	}
+
+	if _, err := ctrl.Refresh(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("unknown: got %v, want ErrInvalidRefreshToken", err)
+	}
+}
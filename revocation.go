@@ -0,0 +1,124 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yogenyslav/authgo/store"
+)
+
+var (
+	ErrRevocationDisabled = errors.New("token revocation is not enabled")
+	ErrTokenRevoked       = errors.New("token is revoked")
+)
+
+// migrator is implemented by revocation lists that keep their data in a database.
+type migrator interface {
+	ApplyMigrations() error
+}
+
+// WithRevocationList enables revocation of access tokens before their expiration.
+func WithRevocationList(l store.RevocationList) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.revocation = l
+	}
+}
+
+// WithRevocationCheck makes middleware reject tokens present in the revocation list.
+func WithRevocationCheck(l store.RevocationList) MiddlewareOption {
+	return func(m *middleware) {
+		m.revocation = l
+	}
+}
+
+// tokenClaims verifies token signature and returns its claims, ignoring expiration and other time checks.
+func (j *jwtProvider) tokenClaims(accessToken string) (*accessClaims, error) {
+	accessToken = strings.TrimPrefix(accessToken, typeBearerToken+" ")
+
+	claims, err := j.parse(accessToken, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return claims, nil
+}
+
+func (ctrl *controller) RevokeToken(ctx context.Context, accessToken string) error {
+	if ctrl.revocation == nil {
+		return ErrRevocationDisabled
+	}
+
+	claims, err := ctrl.jwt.tokenClaims(accessToken)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.revoke(ctx, claims)
+}
+
+// revoke puts token id from claims into revocation list until the token expires,
+// the entry is kept during the leeway as the token is still accepted by parser then.
+func (ctrl *controller) revoke(ctx context.Context, claims *accessClaims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return fmt.Errorf("revoke token: %w", ErrTokenMissingClaim)
+	}
+
+	if err := ctrl.revocation.Revoke(ctx, claims.ID, claims.ExpiresAt.Add(ctrl.jwt.leeway)); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+
+	return nil
+}
+
+func (ctrl *controller) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	claims, err := ctrl.jwt.tokenClaims(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if ctrl.revocation != nil {
+		if err := ctrl.revoke(ctx, claims); err != nil {
+			return err
+		}
+	}
+
+	if ctrl.session != nil && claims.SessionID != "" {
+		if err := ctrl.session.End(ctx, claims.SessionID); err != nil {
+			return fmt.Errorf("end session: %w", err)
+		}
+	}
+
+	if ctrl.refresh != nil && refreshToken != "" {
+		token, err := ctrl.refresh.FindOneByHash(ctx, hashToken(refreshToken))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err)
+		}
+
+		if err := ctrl.refresh.RevokeFamily(ctx, token.FamilyID); err != nil {
+			return fmt.Errorf("revoke token family: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkRevoked returns ErrTokenRevoked if the token id is in the revocation list.
+func checkRevoked(ctx context.Context, l store.RevocationList, jti string) error {
+	if jti == "" {
+		return nil
+	}
+
+	revoked, err := l.IsRevoked(ctx, jti)
+	if err != nil {
+		return fmt.Errorf("check revocation list: %w", err)
+	}
+
+	if revoked {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
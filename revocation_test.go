@@ -0,0 +1,70 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store/memory"
+)
+
+// signClaims signs claims with the active key of the provider, so tests can issue tokens with any exp.
+func signClaims(t *testing.T, j *jwtProvider, claims *accessClaims) string {
+	t.Helper()
+
+	key, err := j.keys.signing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	signed, err := token.SignedString(key.sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestRevokeDuringLeeway(t *testing.T) {
+	ctx := context.Background()
+	cfg := JwtConfig{Secret: "revocation-test-secret", Expire: 1, Leeway: 60}
+	list := memory.NewRevocationList(time.Hour)
+
+	ctrl, err := NewAuthController(AuthConfig{Jwt: cfg}, memory.NewUserStore(memory.NewDB()),
+		memory.NewRoleStore(memory.NewDB()), WithRevocationList(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewAuthMiddlewareWithOptions(cfg, WithRevocationCheck(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the token is expired, but is still accepted within the leeway
+	claims, err := newAccessClaims(model.AuthMeta{UserID: 1, TokenID: "expired-jti"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(-10 * time.Second))
+	token := signClaims(t, ctrl.jwt, claims)
+
+	if _, err := m.RequireAuthContext(ctx, "Bearer "+token); err != nil {
+		t.Fatalf("token within leeway: %v", err)
+	}
+
+	if err := ctrl.RevokeToken(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.RequireAuthContext(ctx, "Bearer "+token); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("revoked token within leeway: got %v, want ErrTokenRevoked", err)
+	}
+}
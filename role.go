@@ -0,0 +1,146 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrInvalidRoleName = errors.New("invalid role name")
+	ErrSystemRole      = errors.New("system role cannot be changed")
+	ErrRoleInUse       = errors.New("role is in use")
+	ErrRoleSelfAssign  = errors.New("role cannot be reassigned to itself")
+)
+
+type roleDeleteMode int
+
+const (
+	roleDeleteRestrict roleDeleteMode = iota
+	roleDeleteCascade
+	roleDeleteReassign
+)
+
+// RoleDeleteStrategy defines what happens to assignments of the role being deleted.
+// Zero value is RestrictRoleDelete.
+type RoleDeleteStrategy struct {
+	mode       roleDeleteMode
+	reassignTo int64
+}
+
+var (
+	// RestrictRoleDelete fails with ErrRoleInUse if the role is still assigned, inherited, inherits other roles
+	// or is requested by pending invites or access requests.
+	RestrictRoleDelete = RoleDeleteStrategy{mode: roleDeleteRestrict}
+	// CascadeRoleDelete removes the role from all of its holders.
+	CascadeRoleDelete = RoleDeleteStrategy{mode: roleDeleteCascade}
+)
+
+// ReassignRoleDelete assigns another role to all holders of the role being deleted.
+func ReassignRoleDelete(roleID int64) RoleDeleteStrategy {
+	return RoleDeleteStrategy{mode: roleDeleteReassign, reassignTo: roleID}
+}
+
+// validateRoleName checks that the name can be used in role expressions.
+func validateRoleName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidRoleName)
+	}
+	for i := 0; i < len(name); i++ {
+		if !isRoleNameChar(name[i]) {
+			return fmt.Errorf("%w %q", ErrInvalidRoleName, name)
+		}
+	}
+	return nil
+}
+
+func (ctrl *controller) CreateRole(ctx context.Context, req model.RoleCreate) (int64, error) {
+	if err := validateRoleName(req.Name); err != nil {
+		return 0, err
+	}
+
+	roleID, err := ctrl.role.InsertOne(ctx, model.RoleDao{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert role: %w", err)
+	}
+	return roleID, nil
+}
+
+// RenameRole changes name of the role. Tokens issued before keep the old name until expiration.
+func (ctrl *controller) RenameRole(ctx context.Context, roleID int64, name string) error {
+	if err := validateRoleName(name); err != nil {
+		return err
+	}
+
+	role, err := ctrl.role.FindOneByID(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("find role: %w", err)
+	}
+	if role.IsSystem {
+		return fmt.Errorf("rename role %q: %w", role.Name, ErrSystemRole)
+	}
+
+	role.Name = name
+	if err := ctrl.role.UpdateOne(ctx, role); err != nil {
+		return fmt.Errorf("update role: %w", err)
+	}
+	return nil
+}
+
+// DeleteRole deletes the role with its permission grants and inheritance and removes it from pending invites,
+// assignments of the role are handled according to the strategy.
+func (ctrl *controller) DeleteRole(ctx context.Context, roleID int64, strategy RoleDeleteStrategy) error {
+	ctx, err := ctrl.role.StartTx(ctx)
+	if err != nil {
+		return fmt.Errorf("role store transaction: %w", err)
+	}
+	defer func() {
+		if err := ctrl.role.RollbackTx(ctx); err != nil {
+			panic(fmt.Errorf("rollback role store transaction: %w", err))
+		}
+	}()
+
+	role, err := ctrl.role.FindOneByID(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("find role: %w", err)
+	}
+	if role.IsSystem {
+		return fmt.Errorf("delete role %q: %w", role.Name, ErrSystemRole)
+	}
+
+	switch strategy.mode {
+	case roleDeleteRestrict:
+		count, err := ctrl.role.CountAssignments(ctx, roleID)
+		if err != nil {
+			return fmt.Errorf("count role assignments: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("delete role %q: %w", role.Name, ErrRoleInUse)
+		}
+	case roleDeleteReassign:
+		if strategy.reassignTo == roleID {
+			return fmt.Errorf("delete role %q: %w", role.Name, ErrRoleSelfAssign)
+		}
+		if _, err := ctrl.role.FindOneByID(ctx, strategy.reassignTo); err != nil {
+			return fmt.Errorf("find reassign role: %w", err)
+		}
+		if err := ctrl.role.ReassignRole(ctx, roleID, strategy.reassignTo); err != nil {
+			return fmt.Errorf("reassign role: %w", err)
+		}
+	}
+
+	if err := ctrl.role.DeleteOne(ctx, roleID); err != nil {
+		return fmt.Errorf("delete role: %w", err)
+	}
+
+	if err := ctrl.role.CommitTx(ctx); err != nil {
+		return fmt.Errorf("commit role transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package authgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrInvalidRoleExpr = errors.New("invalid role expression")
+)
+
+// RoleError is returned when the user doesn't satisfy role requirement, it wraps ErrForbidden.
+type RoleError struct {
+	// Requirement is a description of the failed requirement, e.g. "all of [admin editor]".
+	Requirement string
+	// Missing are required roles the user doesn't have.
+	Missing []string
+	// Denied are roles the user must not have, but has.
+	Denied []string
+}
+
+func (e *RoleError) Error() string {
+	var b strings.Builder
+	b.WriteString("role requirement ")
+	b.WriteString(e.Requirement)
+	b.WriteString(" is not satisfied")
+	if len(e.Missing) > 0 {
+		b.WriteString(": missing ")
+		b.WriteString(strings.Join(e.Missing, ", "))
+	}
+	if len(e.Denied) > 0 {
+		if len(e.Missing) > 0 {
+			b.WriteString("; has denied ")
+		} else {
+			b.WriteString(": has denied ")
+		}
+		b.WriteString(strings.Join(e.Denied, ", "))
+	}
+	return b.String()
+}
+
+func (e *RoleError) Unwrap() error {
+	return ErrForbidden
+}
+
+// add appends the role to missing or denied ones, duplicates are skipped.
+func (e *RoleError) add(role string, denied bool) {
+	list := &e.Missing
+	if denied {
+		list = &e.Denied
+	}
+	for _, r := range *list {
+		if r == role {
+			return
+		}
+	}
+	*list = append(*list, role)
+}
+
+// roleSet is a set of role names of the user.
+type roleSet map[string]struct{}
+
+func newRoleSet(roles []model.RoleDto) roleSet {
+	set := make(roleSet, len(roles))
+	for _, role := range roles {
+		set[role.Name] = struct{}{}
+	}
+	return set
+}
+
+// RoleExpr is a compiled boolean expression over role names, e.g. `admin || (editor && !suspended)`.
+// Operators are `!` (not), `&&` (and), `||` (or) in the order of precedence, parentheses group subexpressions.
+// RoleExpr is immutable and safe for concurrent use.
+type RoleExpr struct {
+	src  string
+	root roleNode
+}
+
+// CompileRoleExpr parses the expression, it is meant to be called once at startup.
+func CompileRoleExpr(src string) (*RoleExpr, error) {
+	p := &roleParser{src: src}
+	p.next()
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+
+	return &RoleExpr{src: src, root: root}, nil
+}
+
+// MustCompileRoleExpr is like CompileRoleExpr but panics if the expression is invalid.
+func MustCompileRoleExpr(src string) *RoleExpr {
+	expr, err := CompileRoleExpr(src)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String returns source of the expression.
+func (e *RoleExpr) String() string {
+	return e.src
+}
+
+// Check evaluates the expression against roles, RoleError lists roles that caused the failure.
+func (e *RoleExpr) Check(roles []model.RoleDto) error {
+	set := newRoleSet(roles)
+	if e.root.eval(set) {
+		return nil
+	}
+
+	err := &RoleError{Requirement: fmt.Sprintf("%q", e.src)}
+	e.root.blame(set, true, err)
+	return err
+}
+
+// roleNode is a node of compiled role expression.
+type roleNode interface {
+	eval(set roleSet) bool
+	// blame records into err why the node doesn't evaluate to want.
+	blame(set roleSet, want bool, err *RoleError)
+}
+
+type roleName string
+
+func (n roleName) eval(set roleSet) bool {
+	_, ok := set[string(n)]
+	return ok
+}
+
+func (n roleName) blame(_ roleSet, want bool, err *RoleError) {
+	err.add(string(n), !want)
+}
+
+type roleNot struct {
+	x roleNode
+}
+
+func (n roleNot) eval(set roleSet) bool {
+	return !n.x.eval(set)
+}
+
+func (n roleNot) blame(set roleSet, want bool, err *RoleError) {
+	n.x.blame(set, !want, err)
+}
+
+type roleAnd struct {
+	x, y roleNode
+}
+
+func (n roleAnd) eval(set roleSet) bool {
+	return n.x.eval(set) && n.y.eval(set)
+}
+
+func (n roleAnd) blame(set roleSet, want bool, err *RoleError) {
+	// want true: blame failed operands, want false: both operands are true and both are to blame
+	for _, x := range []roleNode{n.x, n.y} {
+		if x.eval(set) != want {
+			x.blame(set, want, err)
+		}
+	}
+}
+
+type roleOr struct {
+	x, y roleNode
+}
+
+func (n roleOr) eval(set roleSet) bool {
+	return n.x.eval(set) || n.y.eval(set)
+}
+
+func (n roleOr) blame(set roleSet, want bool, err *RoleError) {
+	// want true: both operands are false, want false: blame operands that are true
+	for _, x := range []roleNode{n.x, n.y} {
+		if x.eval(set) != want {
+			x.blame(set, want, err)
+		}
+	}
+}
+
+type roleTokenKind uint8
+
+const (
+	tokEOF roleTokenKind = iota
+	tokName
+	tokNot
+	tokAnd
+	tokOr
+	tokLParen
+	tokRParen
+	tokInvalid
+)
+
+type roleToken struct {
+	kind roleTokenKind
+	text string
+	pos  int
+}
+
+// roleParser is a recursive descent parser of role expressions:
+//
+//	or   = and { "||" and }
+//	and  = not { "&&" not }
+//	not  = "!" not | "(" or ")" | name
+type roleParser struct {
+	src string
+	pos int
+	tok roleToken
+}
+
+func (p *roleParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w %q at %d: %s", ErrInvalidRoleExpr, p.src, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next reads the next token.
+func (p *roleParser) next() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = roleToken{kind: tokEOF, text: "end of expression", pos: start}
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "&&"):
+		p.pos += 2
+		p.tok = roleToken{kind: tokAnd, text: "&&", pos: start}
+	case strings.HasPrefix(p.src[p.pos:], "||"):
+		p.pos += 2
+		p.tok = roleToken{kind: tokOr, text: "||", pos: start}
+	case p.src[p.pos] == '!':
+		p.pos++
+		p.tok = roleToken{kind: tokNot, text: "!", pos: start}
+	case p.src[p.pos] == '(':
+		p.pos++
+		p.tok = roleToken{kind: tokLParen, text: "(", pos: start}
+	case p.src[p.pos] == ')':
+		p.pos++
+		p.tok = roleToken{kind: tokRParen, text: ")", pos: start}
+	case isRoleNameChar(p.src[p.pos]):
+		for p.pos < len(p.src) && isRoleNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = roleToken{kind: tokName, text: p.src[start:p.pos], pos: start}
+	default:
+		p.pos++
+		p.tok = roleToken{kind: tokInvalid, text: p.src[start:p.pos], pos: start}
+	}
+}
+
+// isRoleNameChar reports whether c can be a part of role name.
+func isRoleNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == ':'
+}
+
+func (p *roleParser) parseOr() (roleNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokOr {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = roleOr{x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *roleParser) parseAnd() (roleNode, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokAnd {
+		p.next()
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = roleAnd{x: x, y: y}
+	}
+
+	return x, nil
+}
+
+func (p *roleParser) parseNot() (roleNode, error) {
+	switch p.tok.kind {
+	case tokNot:
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return roleNot{x: x}, nil
+	case tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected \")\", got %q", p.tok.text)
+		}
+		p.next()
+		return x, nil
+	case tokName:
+		name := roleName(p.tok.text)
+		p.next()
+		return name, nil
+	default:
+		return nil, p.errorf("expected role name, got %q", p.tok.text)
+	}
+}
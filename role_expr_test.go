@@ -0,0 +1,115 @@
+package authgo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestCompileRoleExprPrecedence(t *testing.T) {
+	a, b, c := roleName("a"), roleName("b"), roleName("c")
+
+	tests := []struct {
+		src  string
+		want roleNode
+	}{
+		{src: "a", want: a},
+		{src: "a || b && c", want: roleOr{x: a, y: roleAnd{x: b, y: c}}},
+		{src: "a && b || c", want: roleOr{x: roleAnd{x: a, y: b}, y: c}},
+		{src: "!a && b", want: roleAnd{x: roleNot{x: a}, y: b}},
+		{src: "!(a && b)", want: roleNot{x: roleAnd{x: a, y: b}}},
+		{src: "!!a", want: roleNot{x: roleNot{x: a}}},
+		{src: "(a || b) && c", want: roleAnd{x: roleOr{x: a, y: b}, y: c}},
+		{src: "a && b && c", want: roleAnd{x: roleAnd{x: a, y: b}, y: c}},
+		{src: "a || b || c", want: roleOr{x: roleOr{x: a, y: b}, y: c}},
+		{src: " ( a )\t&&\n!b ", want: roleAnd{x: a, y: roleNot{x: b}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := CompileRoleExpr(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expr.root, tt.want) {
+				t.Errorf("got %#v, want %#v", expr.root, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRoleExprMalformed(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"   ",
+		"a &&",
+		"&& a",
+		"a ||| b",
+		"a || || b",
+		"(a",
+		"a)",
+		"()",
+		"a b",
+		"a & b",
+		"a | b",
+		"!",
+		"admin$",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := CompileRoleExpr(src); !errors.Is(err, ErrInvalidRoleExpr) {
+				t.Errorf("got %v, want ErrInvalidRoleExpr", err)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompileRoleExpr doesn't panic on malformed expression")
+		}
+	}()
+	MustCompileRoleExpr("a &&")
+}
+
+func TestRoleExprCheck(t *testing.T) {
+	expr := MustCompileRoleExpr("admin || editor && !suspended")
+	roles := func(names ...string) []model.RoleDto {
+		dto := make([]model.RoleDto, len(names))
+		for i, name := range names {
+			dto[i] = model.RoleDto{Name: name}
+		}
+		return dto
+	}
+
+	tests := []struct {
+		name    string
+		roles   []model.RoleDto
+		ok      bool
+		missing []string
+		denied  []string
+	}{
+		{name: "admin", roles: roles("admin", "suspended"), ok: true},
+		{name: "editor", roles: roles("editor"), ok: true},
+		{name: "suspended editor", roles: roles("editor", "suspended"), missing: []string{"admin"}, denied: []string{"suspended"}},
+		{name: "no roles", missing: []string{"admin", "editor"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := expr.Check(tt.roles)
+			if tt.ok {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var roleErr *RoleError
+			if !errors.As(err, &roleErr) || !errors.Is(err, ErrForbidden) {
+				t.Fatalf("got %v, want RoleError", err)
+			}
+			if !reflect.DeepEqual(roleErr.Missing, tt.missing) || !reflect.DeepEqual(roleErr.Denied, tt.denied) {
+				t.Errorf("missing %v, denied %v, want %v, %v", roleErr.Missing, roleErr.Denied, tt.missing, tt.denied)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestDeleteRole(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := newTestController(t, AuthConfig{})
+	userID := registerUser(t, ctrl, "user").Meta.UserID
+	ids := createRoles(t, ctrl, "assigned", "parent", "inherited", "unused", "cascade", "from", "to")
+	for _, name := range []string{"assigned", "cascade", "from"} {
+		if err := ctrl.SetRole(ctx, userID, ids[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ctrl.AddInheritedRole(ctx, ids["parent"], ids["inherited"]); err != nil {
+		t.Fatal(err)
+	}
+
+	holds := func(name string) bool {
+		roles, err := ctrl.role.ListUserRoles(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return slices.ContainsFunc(roles, func(r model.RoleDao) bool { return r.ID == ids[name] })
+	}
+
+	defaultRole, err := ctrl.role.FindOneByName(ctx, model.DefaultRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.DeleteRole(ctx, defaultRole.ID, CascadeRoleDelete); !errors.Is(err, ErrSystemRole) {
+		t.Errorf("system role: got %v, want ErrSystemRole", err)
+	}
+
+	t.Run("restrict", func(t *testing.T) {
+		for _, name := range []string{"assigned", "parent", "inherited"} {
+			if err := ctrl.DeleteRole(ctx, ids[name], RestrictRoleDelete); !errors.Is(err, ErrRoleInUse) {
+				t.Errorf("%s role: got %v, want ErrRoleInUse", name, err)
+			}
+		}
+		if err := ctrl.DeleteRole(ctx, ids["unused"], RestrictRoleDelete); err != nil {
+			t.Fatalf("unused role: %v", err)
+		}
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		if err := ctrl.DeleteRole(ctx, ids["cascade"], CascadeRoleDelete); err != nil {
+			t.Fatal(err)
+		}
+		if holds("cascade") {
+			t.Errorf("user holds deleted role")
+		}
+		if !holds("assigned") {
+			t.Errorf("user lost another role")
+		}
+	})
+
+	t.Run("reassign", func(t *testing.T) {
+		if err := ctrl.DeleteRole(ctx, ids["from"], ReassignRoleDelete(ids["from"])); !errors.Is(err, ErrRoleSelfAssign) {
+			t.Errorf("reassign to itself: got %v, want ErrRoleSelfAssign", err)
+		}
+		if err := ctrl.DeleteRole(ctx, ids["from"], ReassignRoleDelete(ids["to"])); err != nil {
+			t.Fatal(err)
+		}
+		if holds("from") || !holds("to") {
+			t.Errorf("role is not reassigned")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package authgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+	"github.com/yogenyslav/authgo/store"
+)
+
+const (
+	// defaultSessionExpire is used when SessionConfig.Expire is not set (30 days).
+	defaultSessionExpire int = 24 * 30
+	// sessionIDSize is a number of random bytes in session id.
+	sessionIDSize int = 16
+)
+
+var (
+	ErrSessionsDisabled = errors.New("sessions are not enabled")
+	ErrSessionEnded     = errors.New("session is ended")
+)
+
+// WithSessionStore enables server-side auth sessions persisted in the provided store.
+func WithSessionStore(s store.SessionStore) ControllerOption {
+	return func(ctrl *controller) {
+		ctrl.session = s
+	}
+}
+
+// startSession creates a new session for the user if sessions are enabled and returns its id.
+func (ctrl *controller) startSession(ctx context.Context, userID int64, client model.ClientInfo) (string, error) {
+	if ctrl.session == nil {
+		return "", nil
+	}
+
+	sessionID, err := generateToken(sessionIDSize)
+	if err != nil {
+		return "", fmt.Errorf("generate session id: %w", err)
+	}
+
+	expire := ctrl.cfg.Session.Expire
+	if expire <= 0 {
+		expire = defaultSessionExpire
+	}
+
+	session := model.SessionDao{
+		ID:        sessionID,
+		UserID:    userID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(time.Hour * time.Duration(expire)),
+	}
+	if err := ctrl.session.InsertOne(ctx, session); err != nil {
+		return "", fmt.Errorf("insert session: %w", err)
+	}
+
+	return sessionID, nil
+}
+
+// continueSession checks that the session is still active and marks it as seen.
+func (ctrl *controller) continueSession(ctx context.Context, sessionID string) error {
+	if ctrl.session == nil || sessionID == "" {
+		return nil
+	}
+
+	if err := validateSession(ctx, ctrl.session, sessionID); err != nil {
+		return err
+	}
+
+	if err := ctrl.session.Touch(ctx, sessionID); err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+
+	return nil
+}
+
+// validateSession returns ErrSessionEnded if the session is ended, expired or doesn't exist.
+func validateSession(ctx context.Context, s store.SessionStore, sessionID string) error {
+	if sessionID == "" {
+		return ErrSessionEnded
+	}
+
+	session, err := s.FindOneByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSessionEnded, err)
+	}
+
+	if !session.Active(time.Now()) {
+		return ErrSessionEnded
+	}
+
+	return nil
+}
+
+func (ctrl *controller) ListSessions(ctx context.Context, userID int64) ([]model.SessionDto, error) {
+	if ctrl.session == nil {
+		return nil, ErrSessionsDisabled
+	}
+
+	sessionsDB, err := ctrl.session.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user sessions: %w", err)
+	}
+
+	sessions := make([]model.SessionDto, 0, len(sessionsDB))
+	for _, session := range sessionsDB {
+		sessions = append(sessions, session.ToDto())
+	}
+	return sessions, nil
+}
+
+func (ctrl *controller) EndSession(ctx context.Context, sessionID string) error {
+	if ctrl.session == nil {
+		return ErrSessionsDisabled
+	}
+	return ctrl.session.End(ctx, sessionID)
+}
+
+func (ctrl *controller) EndAllSessions(ctx context.Context, userID int64) error {
+	if ctrl.session == nil {
+		return ErrSessionsDisabled
+	}
+	return ctrl.session.EndAllUserSessions(ctx, userID)
+}
@@ -0,0 +1,28 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// AccessRequestStore provides methods to manipulate with access requests and their audit events.
+type AccessRequestStore interface {
+	Store
+	// InsertOne creates a new pending access request.
+	InsertOne(ctx context.Context, req model.AccessRequestDao) (int64, error)
+	// FindOneByID finds an access request by its id.
+	FindOneByID(ctx context.Context, requestID int64) (model.AccessRequestDao, error)
+	// Decide moves a pending access request into the status, it returns false if the request is not pending anymore.
+	Decide(ctx context.Context, requestID int64, status model.AccessRequestStatus, actorID int64) (bool, error)
+	// ExpirePending marks pending requests past their deadline as expired and returns their ids.
+	ExpirePending(ctx context.Context) ([]int64, error)
+	// ListPending returns a list of pending access requests which are not past their deadline.
+	ListPending(ctx context.Context) ([]model.AccessRequestDao, error)
+	// ListUserRequests returns a list of access requests of a certain user.
+	ListUserRequests(ctx context.Context, userID int64) ([]model.AccessRequestDao, error)
+	// InsertEvent records a state change of access request.
+	InsertEvent(ctx context.Context, event model.AccessRequestEventDao) error
+	// ListEvents returns a list of state changes of access request in chronological order.
+	ListEvents(ctx context.Context, requestID int64) ([]model.AccessRequestEventDao, error)
+}
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// InviteStore provides methods to manipulate with invites.
+type InviteStore interface {
+	Store
+	// InsertOne creates a new invite.
+	InsertOne(ctx context.Context, invite model.InviteDao) (int64, error)
+	// FindOneByHash finds an invite by hash of its token.
+	FindOneByHash(ctx context.Context, tokenHash string) (model.InviteDao, error)
+	// MarkAccepted marks the invite accepted, it returns false if the invite is not pending anymore.
+	MarkAccepted(ctx context.Context, inviteID int64) (bool, error)
+	// Revoke revokes a pending invite.
+	Revoke(ctx context.Context, inviteID int64) error
+	// ListPending returns a list of pending invites into organization, zero orgID lists invites without organization.
+	ListPending(ctx context.Context, orgID int64) ([]model.InviteDao, error)
+}
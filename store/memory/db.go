@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrNoTxFound = errors.New("no transaction found in context")
+	ErrTxClosed  = errors.New("transaction is already closed")
+	// ErrTxConflict is returned on commit if any other write was committed after this transaction started,
+	// even if it changed unrelated records.
+	ErrTxConflict = errors.New("transaction conflicts with a concurrent one")
+)
+
+// userRoleKey identifies a role assignment.
+type userRoleKey struct {
+	userID int64
+	roleID int64
+}
+
+// userRoleGrant is a role assignment, ExpiresAt is nil for permanent ones.
+type userRoleGrant struct {
+	expiresAt *time.Time
+	grantedBy int64
+	reason    string
+}
+
+// tupleVersion is a relation tuple visible from createdRevision until deletedRevision, which is 0 for live tuples.
+type tupleVersion struct {
+	tuple           model.RelationTuple
+	createdRevision int64
+	deletedRevision int64
+}
+
+// state is a snapshot of all data, it is copied on transaction start and never changed after commit.
+type state struct {
+	version int64
+
+	nextUserID       int64
+	nextRoleID       int64
+	nextPermissionID int64
+
+	users       map[int64]model.UserDao
+	roles       map[int64]model.RoleDao
+	permissions map[int64]model.PermissionDao
+	userRoles   map[userRoleKey]userRoleGrant
+	// inheritance maps role id to ids of roles it inherits directly.
+	inheritance map[int64]map[int64]struct{}
+	// rolePermissions maps role id to ids of permissions granted to it.
+	rolePermissions map[int64]map[int64]struct{}
+
+	tupleRevision int64
+	tuples        []tupleVersion
+}
+
+func (s *state) clone() *state {
+	c := *s
+	c.users = maps.Clone(s.users)
+	c.roles = maps.Clone(s.roles)
+	c.permissions = maps.Clone(s.permissions)
+	c.userRoles = maps.Clone(s.userRoles)
+	c.inheritance = cloneSets(s.inheritance)
+	c.rolePermissions = cloneSets(s.rolePermissions)
+	c.tuples = slices.Clone(s.tuples)
+	return &c
+}
+
+func cloneSets(m map[int64]map[int64]struct{}) map[int64]map[int64]struct{} {
+	c := make(map[int64]map[int64]struct{}, len(m))
+	for k, set := range m {
+		c[k] = maps.Clone(set)
+	}
+	return c
+}
+
+// tx is a transaction with a private copy of the state.
+type tx struct {
+	state  *state
+	closed bool
+}
+
+type txKey struct {
+	db *DB
+}
+
+// DB is an in-memory database shared by memory stores, it is safe for concurrent use.
+// Transactions work on a copy of the data taken at StartTx and replace the data on commit,
+// commit fails with ErrTxConflict if another write was committed in between. Conflicts are detected
+// for the whole database rather than per record, so it is stricter than postgres: concurrent transactions
+// that touch unrelated records may fail as well and should be retried.
+// Missing records are reported with pgx.ErrNoRows as in postgres stores.
+type DB struct {
+	mu    sync.RWMutex
+	state *state
+}
+
+// NewDB creates an empty in-memory database with the default role.
+func NewDB() *DB {
+	s := &state{
+		users:           make(map[int64]model.UserDao),
+		roles:           make(map[int64]model.RoleDao),
+		permissions:     make(map[int64]model.PermissionDao),
+		userRoles:       make(map[userRoleKey]userRoleGrant),
+		inheritance:     make(map[int64]map[int64]struct{}),
+		rolePermissions: make(map[int64]map[int64]struct{}),
+	}
+
+	s.nextRoleID++
+	s.roles[s.nextRoleID] = model.RoleDao{
+		ID:        s.nextRoleID,
+		Name:      model.DefaultRole,
+		IsSystem:  true,
+		CreatedAt: time.Now(),
+	}
+
+	return &DB{
+		state: s,
+	}
+}
+
+// StartTx starts a new transaction and puts it into the context.Context.
+func (db *DB) StartTx(ctx context.Context) (context.Context, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return context.WithValue(ctx, txKey{db: db}, &tx{state: db.state.clone()}), nil
+}
+
+// CommitTx commits current transaction from context.
+func (db *DB) CommitTx(ctx context.Context) error {
+	t, ok := ctx.Value(txKey{db: db}).(*tx)
+	if !ok {
+		return fmt.Errorf("commit transaction: %w", ErrNoTxFound)
+	}
+	if t.closed {
+		return fmt.Errorf("commit transaction: %w", ErrTxClosed)
+	}
+	t.closed = true
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if t.state.version != db.state.version {
+		return fmt.Errorf("commit transaction: %w", ErrTxConflict)
+	}
+	t.state.version++
+	db.state = t.state
+
+	return nil
+}
+
+// RollbackTx rolls back current transaction from context, rolling back a closed transaction is a no-op.
+func (db *DB) RollbackTx(ctx context.Context) error {
+	t, ok := ctx.Value(txKey{db: db}).(*tx)
+	if !ok {
+		return fmt.Errorf("rollback transaction: %w", ErrNoTxFound)
+	}
+	t.closed = true
+	return nil
+}
+
+// read calls fn with the state of transaction from context or with the committed state.
+func (db *DB) read(ctx context.Context, fn func(s *state) error) error {
+	if t, ok := ctx.Value(txKey{db: db}).(*tx); ok {
+		if t.closed {
+			return ErrTxClosed
+		}
+		return fn(t.state)
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return fn(db.state)
+}
+
+// write calls fn with the state of transaction from context,
+// without transaction fn changes a copy of the committed state, which is committed if fn succeeds.
+func (db *DB) write(ctx context.Context, fn func(s *state) error) error {
+	if t, ok := ctx.Value(txKey{db: db}).(*tx); ok {
+		if t.closed {
+			return ErrTxClosed
+		}
+		return fn(t.state)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s := db.state.clone()
+	if err := fn(s); err != nil {
+		return err
+	}
+	s.version++
+	db.state = s
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestTxIsolation(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+
+	txCtx, err := db.StartTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := users.InsertOne(txCtx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := users.FindOneByID(txCtx, userID); err != nil {
+		t.Fatalf("transaction doesn't see own write: %v", err)
+	}
+	if _, err := users.FindOneByID(ctx, userID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("uncommitted write is visible: %v", err)
+	}
+
+	if err := db.CommitTx(txCtx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.FindOneByID(ctx, userID); err != nil {
+		t.Fatalf("committed write is not visible: %v", err)
+	}
+	if err := db.RollbackTx(txCtx); err != nil {
+		t.Fatalf("rollback after commit: %v", err)
+	}
+	if _, err := users.FindOneByID(txCtx, userID); !errors.Is(err, ErrTxClosed) {
+		t.Fatalf("closed transaction: got %v, want ErrTxClosed", err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+
+	txCtx, err := db.StartTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := users.InsertOne(txCtx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RollbackTx(txCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := users.FindOneByID(ctx, userID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("rolled back write is visible: %v", err)
+	}
+	if err := db.CommitTx(txCtx); !errors.Is(err, ErrTxClosed) {
+		t.Fatalf("commit after rollback: got %v, want ErrTxClosed", err)
+	}
+	if err := db.CommitTx(ctx); !errors.Is(err, ErrNoTxFound) {
+		t.Fatalf("commit without transaction: got %v, want ErrNoTxFound", err)
+	}
+}
+
+func TestTxConflict(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+
+	first, err := db.StartTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := db.StartTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := users.InsertOne(first, model.UserDao{Email: "first@example.com", Username: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.InsertOne(second, model.UserDao{Email: "second@example.com", Username: "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.CommitTx(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CommitTx(second); !errors.Is(err, ErrTxConflict) {
+		t.Fatalf("concurrent commit: got %v, want ErrTxConflict", err)
+	}
+
+	list, err := users.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Email != "first@example.com" {
+		t.Fatalf("users = %+v, want only the first one", list)
+	}
+}
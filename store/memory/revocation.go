@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// revocationList is an in-memory RevocationList.
+type revocationList struct {
+	mu            sync.RWMutex
+	entries       map[string]time.Time
+	pruneInterval time.Duration
+	lastPrune     time.Time
+}
+
+// NewRevocationList creates an in-memory RevocationList that prunes expired entries
+// on writes at most once per pruneInterval.
+func NewRevocationList(pruneInterval time.Duration) *revocationList {
+	return &revocationList{
+		entries:       make(map[string]time.Time),
+		pruneInterval: pruneInterval,
+		lastPrune:     time.Now(),
+	}
+}
+
+func (l *revocationList) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if expiresAt.After(now) {
+		l.entries[jti] = expiresAt
+	}
+
+	if now.Sub(l.lastPrune) >= l.pruneInterval {
+		l.prune(now)
+	}
+
+	return nil
+}
+
+func (l *revocationList) IsRevoked(_ context.Context, jti string) (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	expiresAt, ok := l.entries[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (l *revocationList) Prune(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(time.Now())
+	return nil
+}
+
+// prune removes expired entries, must be called with mu locked.
+func (l *revocationList) prune(now time.Time) {
+	for jti, expiresAt := range l.entries {
+		if !now.Before(expiresAt) {
+			delete(l.entries, jti)
+		}
+	}
+	l.lastPrune = now
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRevocationList(t *testing.T) {
+	ctx := context.Background()
+	l := NewRevocationList(time.Hour)
+
+	if err := l.Revoke(ctx, "active", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Revoke(ctx, "expired", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	for jti, want := range map[string]bool{"active": true, "expired": false, "unknown": false} {
+		revoked, err := l.IsRevoked(ctx, jti)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if revoked != want {
+			t.Errorf("IsRevoked(%q) = %v, want %v", jti, revoked, want)
+		}
+	}
+
+	l.entries["stale"] = time.Now().Add(-time.Second)
+	if err := l.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l.entries["stale"]; ok {
+		t.Errorf("expired entry is not pruned")
+	}
+	if _, ok := l.entries["active"]; !ok {
+		t.Errorf("active entry is pruned")
+	}
+}
@@ -0,0 +1,498 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrDuplicateRoleName = errors.New("role with this name already exists")
+)
+
+type roleStore struct {
+	db *DB
+}
+
+// NewRoleStore creates an in-memory RoleStore over the database.
+func NewRoleStore(db *DB) *roleStore {
+	return &roleStore{
+		db: db,
+	}
+}
+
+func (s *roleStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.db.StartTx(ctx)
+}
+
+func (s *roleStore) CommitTx(ctx context.Context) error {
+	return s.db.CommitTx(ctx)
+}
+
+func (s *roleStore) RollbackTx(ctx context.Context) error {
+	return s.db.RollbackTx(ctx)
+}
+
+func (s *roleStore) ApplyMigrations() error {
+	return nil
+}
+
+// findRoleByName returns a role with the name.
+func findRoleByName(st *state, name string) (model.RoleDao, bool) {
+	for _, r := range st.roles {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return model.RoleDao{}, false
+}
+
+func (s *roleStore) InsertOne(ctx context.Context, role model.RoleDao) (int64, error) {
+	var roleID int64
+
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := findRoleByName(st, role.Name); ok {
+			return ErrDuplicateRoleName
+		}
+
+		st.nextRoleID++
+		role.ID = st.nextRoleID
+		role.CreatedAt = time.Now()
+		role.ExpiresAt = nil
+		st.roles[role.ID] = role
+
+		roleID = role.ID
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert role: %w", err)
+	}
+
+	return roleID, nil
+}
+
+func (s *roleStore) FindOneByID(ctx context.Context, roleID int64) (model.RoleDao, error) {
+	var role model.RoleDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		r, ok := st.roles[roleID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		role = r
+		return nil
+	})
+	if err != nil {
+		return role, fmt.Errorf("find role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *roleStore) FindOneByName(ctx context.Context, name string) (model.RoleDao, error) {
+	var role model.RoleDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		r, ok := findRoleByName(st, name)
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		role = r
+		return nil
+	})
+	if err != nil {
+		return role, fmt.Errorf("find role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *roleStore) UpdateOne(ctx context.Context, role model.RoleDao) error {
+	err := s.db.write(ctx, func(st *state) error {
+		r, ok := st.roles[role.ID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		if other, ok := findRoleByName(st, role.Name); ok && other.ID != role.ID {
+			return ErrDuplicateRoleName
+		}
+
+		r.Name = role.Name
+		r.DisplayName = role.DisplayName
+		r.Description = role.Description
+		st.roles[role.ID] = r
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update role data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleStore) DeleteOne(ctx context.Context, roleID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := st.roles[roleID]; !ok {
+			return pgx.ErrNoRows
+		}
+
+		delete(st.roles, roleID)
+		delete(st.inheritance, roleID)
+		delete(st.rolePermissions, roleID)
+		for _, inherited := range st.inheritance {
+			delete(inherited, roleID)
+		}
+		for key := range st.userRoles {
+			if key.roleID == roleID {
+				delete(st.userRoles, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete role: %w", err)
+	}
+
+	return nil
+}
+
+// activeGrant reports whether the grant is not expired.
+func activeGrant(grant userRoleGrant, now time.Time) bool {
+	return grant.expiresAt == nil || grant.expiresAt.After(now)
+}
+
+// CountAssignments counts grants and inheritance edges, the memory database has no invites and access requests.
+func (s *roleStore) CountAssignments(ctx context.Context, roleID int64) (int64, error) {
+	var count int64
+
+	err := s.db.read(ctx, func(st *state) error {
+		now := time.Now()
+		for key, grant := range st.userRoles {
+			if key.roleID == roleID && activeGrant(grant, now) {
+				count++
+			}
+		}
+		for id, inherited := range st.inheritance {
+			if id == roleID {
+				count += int64(len(inherited))
+				continue
+			}
+			if _, ok := inherited[roleID]; ok {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count role assignments: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *roleStore) ReassignRole(ctx context.Context, fromRoleID, toRoleID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		for key, grant := range st.userRoles {
+			if key.roleID != fromRoleID {
+				continue
+			}
+			delete(st.userRoles, key)
+
+			to := userRoleKey{userID: key.userID, roleID: toRoleID}
+			if _, ok := st.userRoles[to]; !ok {
+				st.userRoles[to] = grant
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reassign user roles: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleStore) ListAll(ctx context.Context) ([]model.RoleDao, error) {
+	var roles []model.RoleDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		roles = make([]model.RoleDao, 0, len(st.roles))
+		for _, r := range st.roles {
+			roles = append(roles, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list all roles: %w", err)
+	}
+
+	sortRoles(roles)
+	return roles, nil
+}
+
+// effectiveRoles returns ids of roles held by the user directly or through inheritance
+// mapped to expiration of the grant, nil expiration means the role is held permanently.
+func effectiveRoles(st *state, userID int64) map[int64]*time.Time {
+	now := time.Now()
+	effective := make(map[int64]*time.Time)
+
+	var visit func(roleID int64, expiresAt *time.Time)
+	visit = func(roleID int64, expiresAt *time.Time) {
+		current, ok := effective[roleID]
+		if ok && !extendsGrant(expiresAt, current) {
+			return
+		}
+		effective[roleID] = expiresAt
+
+		for inherited := range st.inheritance[roleID] {
+			visit(inherited, expiresAt)
+		}
+	}
+
+	for key, grant := range st.userRoles {
+		if key.userID == userID && activeGrant(grant, now) {
+			visit(key.roleID, grant.expiresAt)
+		}
+	}
+	return effective
+}
+
+// extendsGrant reports whether expiration a is later than b.
+func extendsGrant(a, b *time.Time) bool {
+	switch {
+	case b == nil:
+		return false
+	case a == nil:
+		return true
+	default:
+		return a.After(*b)
+	}
+}
+
+func (s *roleStore) ListUserRoles(ctx context.Context, userID int64) ([]model.RoleDao, error) {
+	var roles []model.RoleDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		for roleID, expiresAt := range effectiveRoles(st, userID) {
+			r, ok := st.roles[roleID]
+			if !ok {
+				continue
+			}
+			// only the columns returned by postgres store are set
+			roles = append(roles, model.RoleDao{
+				ID:        r.ID,
+				Name:      r.Name,
+				CreatedAt: r.CreatedAt,
+				ExpiresAt: expiresAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list user roles: %w", err)
+	}
+
+	sortRoles(roles)
+	return roles, nil
+}
+
+func (s *roleStore) AddInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) (bool, error) {
+	var added bool
+
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := st.roles[roleID]; !ok {
+			return pgx.ErrNoRows
+		}
+		if _, ok := st.roles[inheritedRoleID]; !ok {
+			return pgx.ErrNoRows
+		}
+		if roleID == inheritedRoleID || slices.Contains(impliedRoles(st, inheritedRoleID), roleID) {
+			return nil
+		}
+
+		if st.inheritance[roleID] == nil {
+			st.inheritance[roleID] = make(map[int64]struct{})
+		}
+		st.inheritance[roleID][inheritedRoleID] = struct{}{}
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("add inherited role: %w", err)
+	}
+
+	return added, nil
+}
+
+func (s *roleStore) RemoveInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := st.inheritance[roleID][inheritedRoleID]; !ok {
+			return pgx.ErrNoRows
+		}
+		delete(st.inheritance[roleID], inheritedRoleID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("remove inherited role: %w", err)
+	}
+
+	return nil
+}
+
+// impliedRoles returns ids of roles inherited by the role directly or transitively.
+func impliedRoles(st *state, roleID int64) []int64 {
+	var implied []int64
+	visited := map[int64]struct{}{roleID: {}}
+
+	pending := []int64{roleID}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for inherited := range st.inheritance[current] {
+			if _, ok := visited[inherited]; ok {
+				continue
+			}
+			visited[inherited] = struct{}{}
+			implied = append(implied, inherited)
+			pending = append(pending, inherited)
+		}
+	}
+	return implied
+}
+
+func (s *roleStore) ListInheritedRoles(ctx context.Context, roleID int64) ([]model.RoleDao, error) {
+	var roles []model.RoleDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		for _, inherited := range impliedRoles(st, roleID) {
+			if r, ok := st.roles[inherited]; ok {
+				roles = append(roles, r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list inherited roles: %w", err)
+	}
+
+	sortRoles(roles)
+	return roles, nil
+}
+
+func (s *roleStore) GrantPermission(ctx context.Context, roleID int64, permission string) error {
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := st.roles[roleID]; !ok {
+			return pgx.ErrNoRows
+		}
+
+		permissionID, ok := findPermissionByName(st, permission)
+		if !ok {
+			st.nextPermissionID++
+			permissionID = st.nextPermissionID
+			st.permissions[permissionID] = model.PermissionDao{
+				ID:        permissionID,
+				Name:      permission,
+				CreatedAt: time.Now(),
+			}
+		}
+
+		if st.rolePermissions[roleID] == nil {
+			st.rolePermissions[roleID] = make(map[int64]struct{})
+		}
+		st.rolePermissions[roleID][permissionID] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("grant permission: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleStore) RevokePermission(ctx context.Context, roleID int64, permission string) error {
+	err := s.db.write(ctx, func(st *state) error {
+		permissionID, ok := findPermissionByName(st, permission)
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		if _, ok := st.rolePermissions[roleID][permissionID]; !ok {
+			return pgx.ErrNoRows
+		}
+		delete(st.rolePermissions[roleID], permissionID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("revoke permission: %w", err)
+	}
+
+	return nil
+}
+
+func findPermissionByName(st *state, name string) (int64, bool) {
+	for _, p := range st.permissions {
+		if p.Name == name {
+			return p.ID, true
+		}
+	}
+	return 0, false
+}
+
+func (s *roleStore) ListRolePermissions(ctx context.Context, roleID int64) ([]model.PermissionDao, error) {
+	var permissions []model.PermissionDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		for permissionID := range st.rolePermissions[roleID] {
+			permissions = append(permissions, st.permissions[permissionID])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list role permissions: %w", err)
+	}
+
+	sortPermissions(permissions)
+	return permissions, nil
+}
+
+func (s *roleStore) ListUserPermissions(ctx context.Context, userID int64) ([]model.PermissionDao, error) {
+	var permissions []model.PermissionDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		seen := make(map[int64]struct{})
+		for roleID := range effectiveRoles(st, userID) {
+			for permissionID := range st.rolePermissions[roleID] {
+				if _, ok := seen[permissionID]; ok {
+					continue
+				}
+				seen[permissionID] = struct{}{}
+				permissions = append(permissions, st.permissions[permissionID])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list user permissions: %w", err)
+	}
+
+	sortPermissions(permissions)
+	return permissions, nil
+}
+
+func sortRoles(roles []model.RoleDao) {
+	slices.SortFunc(roles, func(a, b model.RoleDao) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+func sortPermissions(permissions []model.PermissionDao) {
+	slices.SortFunc(permissions, func(a, b model.PermissionDao) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
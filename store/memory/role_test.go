@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestDefaultRole(t *testing.T) {
+	ctx := context.Background()
+	roles := NewRoleStore(NewDB())
+
+	role, err := roles.FindOneByName(ctx, model.DefaultRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !role.IsSystem {
+		t.Fatalf("default role is not a system role")
+	}
+
+	if _, err := roles.InsertOne(ctx, model.RoleDao{Name: model.DefaultRole}); !errors.Is(err, ErrDuplicateRoleName) {
+		t.Fatalf("insert duplicate role: got %v, want ErrDuplicateRoleName", err)
+	}
+}
+
+func TestListUserRoles(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+	roles := NewRoleStore(db)
+
+	userID, err := users.InsertOne(ctx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminID, err := roles.InsertOne(ctx, model.RoleDao{Name: "admin", DisplayName: "Admin", Description: "Full access"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	editorID, err := roles.InsertOne(ctx, model.RoleDao{Name: "editor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roles.AddInheritedRole(ctx, adminID, editorID); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := users.SetRoleUntil(ctx, model.RoleGrant{UserID: userID, RoleID: adminID, ExpiresAt: expiresAt}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := roles.ListUserRoles(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("roles = %+v, want admin and inherited editor", list)
+	}
+	for _, role := range list {
+		if role.DisplayName != "" || role.Description != "" || role.IsSystem {
+			t.Errorf("role %+v: only id, name, created_at and expires_at must be set", role)
+		}
+		if role.ExpiresAt == nil || !role.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("role %q expires at %v, want %v", role.Name, role.ExpiresAt, expiresAt)
+		}
+	}
+}
+
+func TestCountAssignments(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+	roles := NewRoleStore(db)
+
+	userID, err := users.InsertOne(ctx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]int64)
+	for _, name := range []string{"admin", "editor", "expired", "unused"} {
+		ids[name], err = roles.InsertOne(ctx, model.RoleDao{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := roles.AddInheritedRole(ctx, ids["admin"], ids["editor"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetRole(ctx, userID, ids["admin"]); err != nil {
+		t.Fatal(err)
+	}
+	expired := model.RoleGrant{UserID: userID, RoleID: ids["expired"], ExpiresAt: time.Now().Add(time.Millisecond)}
+	if err := users.SetRoleUntil(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	for name, want := range map[string]int64{"admin": 2, "editor": 1, "expired": 0, "unused": 0} {
+		count, err := roles.CountAssignments(ctx, ids[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("CountAssignments(%s) = %d, want %d", name, count, want)
+		}
+	}
+}
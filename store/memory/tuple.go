@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+type tupleStore struct {
+	db *DB
+}
+
+// NewTupleStore creates an in-memory TupleStore over the database.
+func NewTupleStore(db *DB) *tupleStore {
+	return &tupleStore{
+		db: db,
+	}
+}
+
+func (s *tupleStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.db.StartTx(ctx)
+}
+
+func (s *tupleStore) CommitTx(ctx context.Context) error {
+	return s.db.CommitTx(ctx)
+}
+
+func (s *tupleStore) RollbackTx(ctx context.Context) error {
+	return s.db.RollbackTx(ctx)
+}
+
+func (s *tupleStore) ApplyMigrations() error {
+	return nil
+}
+
+// visibleAt reports whether the tuple version exists at revision.
+func (v tupleVersion) visibleAt(revision int64) bool {
+	return v.createdRevision <= revision && (v.deletedRevision == 0 || v.deletedRevision > revision)
+}
+
+func (s *tupleStore) Write(ctx context.Context, inserts, deletes []model.RelationTuple) (int64, error) {
+	var revision int64
+
+	err := s.db.write(ctx, func(st *state) error {
+		st.tupleRevision++
+		revision = st.tupleRevision
+
+		live := make(map[model.RelationTuple]int, len(st.tuples))
+		for i, v := range st.tuples {
+			if v.deletedRevision == 0 {
+				live[v.tuple] = i
+			}
+		}
+
+		for _, t := range deletes {
+			if i, ok := live[t]; ok {
+				st.tuples[i].deletedRevision = revision
+				delete(live, t)
+			}
+		}
+
+		for _, t := range inserts {
+			if _, ok := live[t]; ok {
+				continue
+			}
+			live[t] = len(st.tuples)
+			st.tuples = append(st.tuples, tupleVersion{tuple: t, createdRevision: revision})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("write tuples: %w", err)
+	}
+
+	return revision, nil
+}
+
+func (s *tupleStore) Revision(ctx context.Context) (int64, error) {
+	var revision int64
+
+	err := s.db.read(ctx, func(st *state) error {
+		revision = st.tupleRevision
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("latest revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+func (s *tupleStore) ReadSubjects(
+	ctx context.Context,
+	object model.Object,
+	relation string,
+	revision int64,
+) ([]model.Subject, error) {
+	var subjects []model.Subject
+
+	err := s.db.read(ctx, func(st *state) error {
+		for _, v := range st.tuples {
+			if v.tuple.Object == object && v.tuple.Relation == relation && v.visibleAt(revision) {
+				subjects = append(subjects, v.tuple.Subject)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read subjects: %w", err)
+	}
+
+	return subjects, nil
+}
+
+func (s *tupleStore) ReadBySubject(
+	ctx context.Context,
+	subject model.Subject,
+	revision int64,
+) ([]model.RelationTuple, error) {
+	var tuples []model.RelationTuple
+
+	err := s.db.read(ctx, func(st *state) error {
+		for _, v := range st.tuples {
+			if v.tuple.Subject == subject && v.visibleAt(revision) {
+				tuples = append(tuples, v.tuple)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read tuples by subject: %w", err)
+	}
+
+	return tuples, nil
+}
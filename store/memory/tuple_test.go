@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestTupleStoreRevisions(t *testing.T) {
+	ctx := context.Background()
+	s := NewTupleStore(NewDB())
+
+	document := model.Object{Namespace: "document", ID: "d1"}
+	alice := model.Subject{Object: model.Object{Namespace: "user", ID: "alice"}}
+	bob := model.Subject{Object: model.Object{Namespace: "user", ID: "bob"}}
+	viewer := func(s model.Subject) model.RelationTuple {
+		return model.RelationTuple{Object: document, Relation: "viewer", Subject: s}
+	}
+
+	first, err := s.Write(ctx, []model.RelationTuple{viewer(alice), viewer(alice)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Write(ctx, []model.RelationTuple{viewer(bob), viewer(alice)}, []model.RelationTuple{viewer(alice)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	third, err := s.Write(ctx, nil, []model.RelationTuple{viewer(alice), viewer(bob)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest, err := s.Revision(ctx); err != nil || latest != third {
+		t.Fatalf("Revision() = %d, %v, want %d", latest, err, third)
+	}
+
+	for _, tt := range []struct {
+		revision int64
+		want     []model.Subject
+	}{
+		{revision: 0},
+		{revision: first, want: []model.Subject{alice}},
+		// alice is deleted and inserted again by the same write
+		{revision: second, want: []model.Subject{alice, bob}},
+		{revision: third},
+	} {
+		subjects, err := s.ReadSubjects(ctx, document, "viewer", tt.revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.SortFunc(subjects, func(a, b model.Subject) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		if !reflect.DeepEqual(subjects, tt.want) {
+			t.Errorf("ReadSubjects at %d = %v, want %v", tt.revision, subjects, tt.want)
+		}
+	}
+
+	tuples, err := s.ReadBySubject(ctx, bob, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []model.RelationTuple{viewer(bob)}; !reflect.DeepEqual(tuples, want) {
+		t.Errorf("ReadBySubject = %v, want %v", tuples, want)
+	}
+}
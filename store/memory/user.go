@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yogenyslav/authgo/model"
+)
+
+var (
+	ErrDuplicateEmail    = errors.New("user with this email already exists")
+	ErrDuplicateUsername = errors.New("user with this username already exists")
+)
+
+type userStore struct {
+	db *DB
+}
+
+// NewUserStore creates an in-memory UserStore over the database.
+func NewUserStore(db *DB) *userStore {
+	return &userStore{
+		db: db,
+	}
+}
+
+func (s *userStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.db.StartTx(ctx)
+}
+
+func (s *userStore) CommitTx(ctx context.Context) error {
+	return s.db.CommitTx(ctx)
+}
+
+func (s *userStore) RollbackTx(ctx context.Context) error {
+	return s.db.RollbackTx(ctx)
+}
+
+func (s *userStore) ApplyMigrations() error {
+	return nil
+}
+
+// checkUnique returns an error if another user has the same email or username.
+func checkUnique(st *state, user model.UserDao) error {
+	for _, u := range st.users {
+		if u.ID == user.ID {
+			continue
+		}
+		if u.Email == user.Email {
+			return ErrDuplicateEmail
+		}
+		if u.Username == user.Username {
+			return ErrDuplicateUsername
+		}
+	}
+	return nil
+}
+
+func (s *userStore) InsertOne(ctx context.Context, user model.UserDao) (int64, error) {
+	var userID int64
+
+	err := s.db.write(ctx, func(st *state) error {
+		user.ID = 0
+		if err := checkUnique(st, user); err != nil {
+			return err
+		}
+
+		st.nextUserID++
+		now := time.Now()
+		user.ID = st.nextUserID
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		user.IsDeleted = false
+		st.users[user.ID] = user
+
+		userID = user.ID
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert user: %w", err)
+	}
+
+	return userID, nil
+}
+
+func (s *userStore) FindOneByID(ctx context.Context, id int64) (model.UserDao, error) {
+	var user model.UserDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		u, ok := st.users[id]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		user = u
+		return nil
+	})
+	if err != nil {
+		return user, fmt.Errorf("find user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *userStore) FindOneByEmail(ctx context.Context, email string) (model.UserDao, error) {
+	var user model.UserDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		for _, u := range st.users {
+			if u.Email == email {
+				user = u
+				return nil
+			}
+		}
+		return pgx.ErrNoRows
+	})
+	if err != nil {
+		return user, fmt.Errorf("find user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *userStore) UpdateOne(ctx context.Context, user model.UserDao) error {
+	err := s.db.write(ctx, func(st *state) error {
+		u, ok := st.users[user.ID]
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		if err := checkUnique(st, user); err != nil {
+			return err
+		}
+
+		u.Email = user.Email
+		u.HashPassword = user.HashPassword
+		u.Username = user.Username
+		u.FirstName = user.FirstName
+		u.LastName = user.LastName
+		u.MiddleName = user.MiddleName
+		u.UpdatedAt = time.Now()
+		st.users[user.ID] = u
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update user data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userStore) DeleteOne(ctx context.Context, userID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		if _, ok := st.users[userID]; !ok {
+			return pgx.ErrNoRows
+		}
+		delete(st.users, userID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userStore) ListAll(ctx context.Context) ([]model.UserDao, error) {
+	var users []model.UserDao
+
+	err := s.db.read(ctx, func(st *state) error {
+		users = make([]model.UserDao, 0, len(st.users))
+		for _, u := range st.users {
+			users = append(users, u)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list all users: %w", err)
+	}
+
+	slices.SortFunc(users, func(a, b model.UserDao) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return users, nil
+}
+
+func (s *userStore) SetRole(ctx context.Context, userID, roleID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		key := userRoleKey{userID: userID, roleID: roleID}
+		grant := st.userRoles[key]
+		grant.expiresAt = nil
+		st.userRoles[key] = grant
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("insert user role: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userStore) SetRoleUntil(ctx context.Context, grant model.RoleGrant) error {
+	err := s.db.write(ctx, func(st *state) error {
+		key := userRoleKey{userID: grant.UserID, roleID: grant.RoleID}
+		if existing, ok := st.userRoles[key]; ok && existing.expiresAt == nil {
+			return nil
+		}
+
+		expiresAt := grant.ExpiresAt
+		st.userRoles[key] = userRoleGrant{
+			expiresAt: &expiresAt,
+			grantedBy: grant.GrantedBy,
+			reason:    grant.Reason,
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("insert user role grant: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userStore) RemoveRole(ctx context.Context, userID, roleID int64) error {
+	err := s.db.write(ctx, func(st *state) error {
+		key := userRoleKey{userID: userID, roleID: roleID}
+		if _, ok := st.userRoles[key]; !ok {
+			return pgx.ErrNoRows
+		}
+		delete(st.userRoles, key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("remove role: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+func TestUserUnique(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserStore(NewDB())
+
+	userID, err := users.InsertOne(ctx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := users.InsertOne(ctx, model.UserDao{Email: "other@example.com", Username: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := users.InsertOne(ctx, model.UserDao{Email: "user@example.com", Username: "new"}); !errors.Is(err, ErrDuplicateEmail) {
+		t.Fatalf("insert duplicate email: got %v, want ErrDuplicateEmail", err)
+	}
+	if _, err := users.InsertOne(ctx, model.UserDao{Email: "new@example.com", Username: "user"}); !errors.Is(err, ErrDuplicateUsername) {
+		t.Fatalf("insert duplicate username: got %v, want ErrDuplicateUsername", err)
+	}
+
+	err = users.UpdateOne(ctx, model.UserDao{ID: otherID, Email: "user@example.com", Username: "other"})
+	if !errors.Is(err, ErrDuplicateEmail) {
+		t.Fatalf("update to duplicate email: got %v, want ErrDuplicateEmail", err)
+	}
+	if err := users.UpdateOne(ctx, model.UserDao{ID: userID, Email: "user@example.com", Username: "renamed"}); err != nil {
+		t.Fatalf("update keeping own email: %v", err)
+	}
+}
+
+func TestSetRoleUntilKeepsPermanentGrant(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	users := NewUserStore(db)
+	roles := NewRoleStore(db)
+
+	userID, err := users.InsertOne(ctx, model.UserDao{Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roleID, err := roles.InsertOne(ctx, model.RoleDao{Name: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	grant := model.RoleGrant{UserID: userID, RoleID: roleID, ExpiresAt: expiresAt}
+	if err := users.SetRoleUntil(ctx, grant); err != nil {
+		t.Fatal(err)
+	}
+	grant.ExpiresAt = expiresAt.Add(time.Hour)
+	if err := users.SetRoleUntil(ctx, grant); err != nil {
+		t.Fatal(err)
+	}
+	key := userRoleKey{userID: userID, roleID: roleID}
+	if got := db.state.userRoles[key].expiresAt; got == nil || !got.Equal(grant.ExpiresAt) {
+		t.Fatalf("time-bound grant expires at %v, want %v", got, grant.ExpiresAt)
+	}
+
+	if err := users.SetRole(ctx, userID, roleID); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetRoleUntil(ctx, grant); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.state.userRoles[key].expiresAt; got != nil {
+		t.Fatalf("permanent grant is replaced by one expiring at %v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// OrgStore provides methods to manipulate with organizations, their members and org-scoped roles.
+type OrgStore interface {
+	Store
+	// InsertOne creates a new organization.
+	InsertOne(ctx context.Context, org model.OrganizationDao) (int64, error)
+	// FindOneByID finds an organization by its id.
+	FindOneByID(ctx context.Context, orgID int64) (model.OrganizationDao, error)
+	// DeleteOne deletes an organization with its memberships.
+	DeleteOne(ctx context.Context, orgID int64) error
+	// ListUserOrgs returns a list of organizations the user is a member of.
+	ListUserOrgs(ctx context.Context, userID int64) ([]model.OrganizationDao, error)
+	// AddMember adds user to organization.
+	AddMember(ctx context.Context, orgID, userID int64) error
+	// RemoveMember removes user from organization with all of the user org roles.
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	// IsMember reports whether the user is a member of organization.
+	IsMember(ctx context.Context, orgID, userID int64) (bool, error)
+	// SetRole assigns role to a member of organization.
+	SetRole(ctx context.Context, orgID, userID, roleID int64) error
+	// RemoveRole removes role from a member of organization.
+	RemoveRole(ctx context.Context, orgID, userID, roleID int64) error
+	// ListUserRoles returns a list of roles assigned to a member of organization, including inherited ones.
+	ListUserRoles(ctx context.Context, orgID, userID int64) ([]model.RoleDao, error)
+	// ListUserPermissions returns a list of permissions granted by org roles of a member, including inherited ones.
+	ListUserPermissions(ctx context.Context, orgID, userID int64) ([]model.PermissionDao, error)
+}
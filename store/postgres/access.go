@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type accessRequestStore struct {
+	pg *postgresDB
+}
+
+// NewAccessRequestStore creates an instance of AccessRequestStore over postgres connection.
+func NewAccessRequestStore(pg *postgresDB) *accessRequestStore {
+	return &accessRequestStore{
+		pg: pg,
+	}
+}
+
+func (s *accessRequestStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *accessRequestStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *accessRequestStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *accessRequestStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const insertOneAccessRequest = `
+	insert into authgo.access_request(user_id, role_id, role_name, reason, duration, status, expires_at)
+	values ($1, $2, $3, $4, $5, $6, $7)
+	returning id;
+`
+
+func (s *accessRequestStore) InsertOne(ctx context.Context, req model.AccessRequestDao) (int64, error) {
+	var requestID int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	err = conn.QueryRow(
+		ctx,
+		insertOneAccessRequest,
+		req.UserID,
+		req.RoleID,
+		req.RoleName,
+		req.Reason,
+		req.Duration,
+		req.Status,
+		req.ExpiresAt,
+	).Scan(&requestID)
+	if err != nil {
+		return 0, fmt.Errorf("insert access request: %w", err)
+	}
+
+	return requestID, nil
+}
+
+const findOneAccessRequestByID = `
+	select id, user_id, role_id, role_name, reason, duration, status, created_at, expires_at, decided_by, decided_at
+	from authgo.access_request
+	where id=$1;
+`
+
+func (s *accessRequestStore) FindOneByID(ctx context.Context, requestID int64) (model.AccessRequestDao, error) {
+	var req model.AccessRequestDao
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return req, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, findOneAccessRequestByID, requestID).Scan(
+		&req.ID,
+		&req.UserID,
+		&req.RoleID,
+		&req.RoleName,
+		&req.Reason,
+		&req.Duration,
+		&req.Status,
+		&req.CreatedAt,
+		&req.ExpiresAt,
+		&req.DecidedBy,
+		&req.DecidedAt,
+	); err != nil {
+		return req, fmt.Errorf("find access request: %w", err)
+	}
+
+	return req, nil
+}
+
+const decideAccessRequest = `
+	update authgo.access_request
+	set status=$2, decided_by=$3, decided_at=current_timestamp
+	where id=$1 and status='pending';
+`
+
+func (s *accessRequestStore) Decide(
+	ctx context.Context,
+	requestID int64,
+	status model.AccessRequestStatus,
+	actorID int64,
+) (bool, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, decideAccessRequest, requestID, status, actorID)
+	if err != nil {
+		return false, fmt.Errorf("decide access request: %w", err)
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+const expirePendingAccessRequests = `
+	update authgo.access_request
+	set status='expired', decided_at=current_timestamp
+	where status='pending' and expires_at <= current_timestamp
+	returning id;
+`
+
+func (s *accessRequestStore) ExpirePending(ctx context.Context) ([]int64, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, expirePendingAccessRequests)
+	if err != nil {
+		return nil, fmt.Errorf("expire access requests: %w", err)
+	}
+
+	requestIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("collect expired access requests: %w", err)
+	}
+
+	return requestIDs, nil
+}
+
+const listPendingAccessRequests = `
+	select id, user_id, role_id, role_name, reason, duration, status, created_at, expires_at, decided_by, decided_at
+	from authgo.access_request
+	where status='pending' and expires_at > current_timestamp
+	order by created_at;
+`
+
+func (s *accessRequestStore) ListPending(ctx context.Context) ([]model.AccessRequestDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listPendingAccessRequests)
+	if err != nil {
+		return nil, fmt.Errorf("list pending access requests: %w", err)
+	}
+
+	return collectAccessRequests(rows)
+}
+
+const listUserAccessRequests = `
+	select id, user_id, role_id, role_name, reason, duration, status, created_at, expires_at, decided_by, decided_at
+	from authgo.access_request
+	where user_id=$1
+	order by created_at desc;
+`
+
+func (s *accessRequestStore) ListUserRequests(ctx context.Context, userID int64) ([]model.AccessRequestDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listUserAccessRequests, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user access requests: %w", err)
+	}
+
+	return collectAccessRequests(rows)
+}
+
+func collectAccessRequests(rows pgx.Rows) ([]model.AccessRequestDao, error) {
+	requests, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.AccessRequestDao, error) {
+		var req model.AccessRequestDao
+		err := row.Scan(
+			&req.ID,
+			&req.UserID,
+			&req.RoleID,
+			&req.RoleName,
+			&req.Reason,
+			&req.Duration,
+			&req.Status,
+			&req.CreatedAt,
+			&req.ExpiresAt,
+			&req.DecidedBy,
+			&req.DecidedAt,
+		)
+		return req, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect access requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+const insertAccessRequestEvent = `
+	insert into authgo.access_request_event(request_id, actor_id, status, comment)
+	values ($1, $2, $3, $4);
+`
+
+func (s *accessRequestStore) InsertEvent(ctx context.Context, event model.AccessRequestEventDao) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	_, err = conn.Exec(
+		ctx,
+		insertAccessRequestEvent,
+		event.RequestID,
+		event.ActorID,
+		event.Status,
+		event.Comment,
+	)
+	if err != nil {
+		return fmt.Errorf("insert access request event: %w", err)
+	}
+
+	return nil
+}
+
+const listAccessRequestEvents = `
+	select id, request_id, actor_id, status, comment, created_at
+	from authgo.access_request_event
+	where request_id=$1
+	order by id;
+`
+
+func (s *accessRequestStore) ListEvents(ctx context.Context, requestID int64) ([]model.AccessRequestEventDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listAccessRequestEvents, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("list access request events: %w", err)
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.AccessRequestEventDao, error) {
+		var event model.AccessRequestEventDao
+		err := row.Scan(
+			&event.ID,
+			&event.RequestID,
+			&event.ActorID,
+			&event.Status,
+			&event.Comment,
+			&event.CreatedAt,
+		)
+		return event, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect access request events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type inviteStore struct {
+	pg *postgresDB
+}
+
+// NewInviteStore creates an instance of InviteStore over postgres connection.
+func NewInviteStore(pg *postgresDB) *inviteStore {
+	return &inviteStore{
+		pg: pg,
+	}
+}
+
+func (s *inviteStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *inviteStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *inviteStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *inviteStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const insertOneInvite = `
+	insert into authgo.invite(org_id, email, role_ids, token_hash, invited_by, expires_at)
+	values ($1, $2, $3, $4, $5, $6)
+	returning id;
+`
+
+func (s *inviteStore) InsertOne(ctx context.Context, invite model.InviteDao) (int64, error) {
+	var inviteID int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	err = conn.QueryRow(
+		ctx,
+		insertOneInvite,
+		invite.OrgID,
+		invite.Email,
+		invite.RoleIDs,
+		invite.TokenHash,
+		invite.InvitedBy,
+		invite.ExpiresAt,
+	).Scan(&inviteID)
+	if err != nil {
+		return 0, fmt.Errorf("insert invite: %w", err)
+	}
+
+	return inviteID, nil
+}
+
+const findOneInviteByHash = `
+	select id, org_id, email, role_ids, token_hash, invited_by, created_at, expires_at, accepted_at, revoked_at
+	from authgo.invite
+	where token_hash=$1;
+`
+
+func (s *inviteStore) FindOneByHash(ctx context.Context, tokenHash string) (model.InviteDao, error) {
+	var invite model.InviteDao
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return invite, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, findOneInviteByHash, tokenHash).Scan(
+		&invite.ID,
+		&invite.OrgID,
+		&invite.Email,
+		&invite.RoleIDs,
+		&invite.TokenHash,
+		&invite.InvitedBy,
+		&invite.CreatedAt,
+		&invite.ExpiresAt,
+		&invite.AcceptedAt,
+		&invite.RevokedAt,
+	); err != nil {
+		return invite, fmt.Errorf("find invite: %w", err)
+	}
+
+	return invite, nil
+}
+
+const markInviteAccepted = `
+	update authgo.invite
+	set accepted_at=current_timestamp
+	where id=$1 and accepted_at is null and revoked_at is null and expires_at > current_timestamp;
+`
+
+func (s *inviteStore) MarkAccepted(ctx context.Context, inviteID int64) (bool, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, markInviteAccepted, inviteID)
+	if err != nil {
+		return false, fmt.Errorf("mark invite accepted: %w", err)
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+const revokeInvite = `
+	update authgo.invite
+	set revoked_at=current_timestamp
+	where id=$1 and accepted_at is null and revoked_at is null;
+`
+
+func (s *inviteStore) Revoke(ctx context.Context, inviteID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, revokeInvite, inviteID)
+	if err != nil {
+		return fmt.Errorf("revoke invite: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("revoke invite: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const listPendingInvites = `
+	select id, org_id, email, role_ids, token_hash, invited_by, created_at, expires_at, accepted_at, revoked_at
+	from authgo.invite
+	where org_id=$1 and accepted_at is null and revoked_at is null and expires_at > current_timestamp
+	order by created_at;
+`
+
+func (s *inviteStore) ListPending(ctx context.Context, orgID int64) ([]model.InviteDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listPendingInvites, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list pending invites: %w", err)
+	}
+
+	invites, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.InviteDao, error) {
+		var invite model.InviteDao
+		err := row.Scan(
+			&invite.ID,
+			&invite.OrgID,
+			&invite.Email,
+			&invite.RoleIDs,
+			&invite.TokenHash,
+			&invite.InvitedBy,
+			&invite.CreatedAt,
+			&invite.ExpiresAt,
+			&invite.AcceptedAt,
+			&invite.RevokedAt,
+		)
+		return invite, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect invites: %w", err)
+	}
+
+	return invites, nil
+}
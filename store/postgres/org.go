@@ -0,0 +1,326 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type orgStore struct {
+	pg *postgresDB
+}
+
+// NewOrgStore creates an instance of OrgStore over postgres connection.
+func NewOrgStore(pg *postgresDB) *orgStore {
+	return &orgStore{
+		pg: pg,
+	}
+}
+
+func (s *orgStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *orgStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *orgStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *orgStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const insertOneOrg = `
+	insert into authgo.organization(name, slug)
+	values ($1, $2)
+	returning id;
+`
+
+func (s *orgStore) InsertOne(ctx context.Context, org model.OrganizationDao) (int64, error) {
+	var orgID int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, insertOneOrg, org.Name, org.Slug).Scan(&orgID); err != nil {
+		return 0, fmt.Errorf("insert organization: %w", err)
+	}
+
+	return orgID, nil
+}
+
+const findOneOrgByID = `
+	select id, name, slug, created_at
+	from authgo.organization
+	where id=$1;
+`
+
+func (s *orgStore) FindOneByID(ctx context.Context, orgID int64) (model.OrganizationDao, error) {
+	var org model.OrganizationDao
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return org, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, findOneOrgByID, orgID).Scan(
+		&org.ID,
+		&org.Name,
+		&org.Slug,
+		&org.CreatedAt,
+	); err != nil {
+		return org, fmt.Errorf("find organization: %w", err)
+	}
+
+	return org, nil
+}
+
+const deleteOneOrg = `
+	delete from authgo.organization
+	where id=$1;
+`
+
+func (s *orgStore) DeleteOne(ctx context.Context, orgID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, deleteOneOrg, orgID)
+	if err != nil {
+		return fmt.Errorf("delete organization: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("delete organization: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const listUserOrgs = `
+	select o.id, o.name, o.slug, o.created_at from authgo.organization o
+	join authgo.membership m
+		on m.org_id = o.id
+	where m.user_id = $1
+	order by o.name;
+`
+
+func (s *orgStore) ListUserOrgs(ctx context.Context, userID int64) ([]model.OrganizationDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listUserOrgs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user organizations: %w", err)
+	}
+
+	orgs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.OrganizationDao, error) {
+		var org model.OrganizationDao
+		err := row.Scan(&org.ID, &org.Name, &org.Slug, &org.CreatedAt)
+		return org, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+const addOrgMember = `
+	insert into authgo.membership(org_id, user_id)
+	values ($1, $2)
+	on conflict do nothing;
+`
+
+func (s *orgStore) AddMember(ctx context.Context, orgID, userID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, addOrgMember, orgID, userID); err != nil {
+		return fmt.Errorf("add member: %w", err)
+	}
+
+	return nil
+}
+
+const removeOrgMember = `
+	delete from authgo.membership
+	where org_id=$1 and user_id=$2;
+`
+
+func (s *orgStore) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, removeOrgMember, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("remove member: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("remove member: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const isOrgMember = `
+	select exists(
+		select 1
+		from authgo.membership
+		where org_id=$1 and user_id=$2
+	);
+`
+
+func (s *orgStore) IsMember(ctx context.Context, orgID, userID int64) (bool, error) {
+	var member bool
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, isOrgMember, orgID, userID).Scan(&member); err != nil {
+		return false, fmt.Errorf("check member: %w", err)
+	}
+
+	return member, nil
+}
+
+const setOrgUserRole = `
+	insert into authgo.org_user_role(org_id, user_id, role_id)
+	values ($1, $2, $3)
+	on conflict do nothing;
+`
+
+func (s *orgStore) SetRole(ctx context.Context, orgID, userID, roleID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, setOrgUserRole, orgID, userID, roleID); err != nil {
+		return fmt.Errorf("set org role: %w", err)
+	}
+
+	return nil
+}
+
+const removeOrgUserRole = `
+	delete from authgo.org_user_role
+	where org_id=$1 and user_id=$2 and role_id=$3;
+`
+
+func (s *orgStore) RemoveRole(ctx context.Context, orgID, userID, roleID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, removeOrgUserRole, orgID, userID, roleID)
+	if err != nil {
+		return fmt.Errorf("remove org role: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("remove org role: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const listOrgUserRoles = `
+	with recursive effective_role(role_id) as (
+		select role_id from authgo.org_user_role
+		where org_id = $1 and user_id = $2
+		union
+		select ri.inherited_role_id from authgo.role_inheritance ri
+		join effective_role er
+			on er.role_id = ri.role_id
+	)
+	select r.id, r.name, r.created_at from authgo.role r
+	join effective_role er
+		on er.role_id = r.id;
+`
+
+func (s *orgStore) ListUserRoles(ctx context.Context, orgID, userID int64) ([]model.RoleDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listOrgUserRoles, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list org user roles: %w", err)
+	}
+
+	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RoleDao, error) {
+		var role model.RoleDao
+		err := row.Scan(&role.ID, &role.Name, &role.CreatedAt)
+		return role, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+const listOrgUserPermissions = `
+	with recursive effective_role(role_id) as (
+		select role_id from authgo.org_user_role
+		where org_id = $1 and user_id = $2
+		union
+		select ri.inherited_role_id from authgo.role_inheritance ri
+		join effective_role er
+			on er.role_id = ri.role_id
+	)
+	select distinct p.id, p.name, p.description, p.created_at from authgo.permission p
+	join authgo.role_permission rp
+		on rp.permission_id = p.id
+	join effective_role er
+		on er.role_id = rp.role_id
+	order by p.name;
+`
+
+func (s *orgStore) ListUserPermissions(ctx context.Context, orgID, userID int64) ([]model.PermissionDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listOrgUserPermissions, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list org user permissions: %w", err)
+	}
+
+	permissions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PermissionDao, error) {
+		var permission model.PermissionDao
+		err := row.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+		return permission, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect permissions: %w", err)
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+)
+
+type revocationList struct {
+	pg *postgresDB
+}
+
+// NewRevocationList creates an instance of RevocationList over postgres connection.
+func NewRevocationList(pg *postgresDB) *revocationList {
+	return &revocationList{
+		pg: pg,
+	}
+}
+
+func (l *revocationList) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(l.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const revokeToken = `
+	insert into authgo.revoked_token(jti, expires_at)
+	values ($1, $2)
+	on conflict (jti) do nothing;
+`
+
+func (l *revocationList) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	conn, err := l.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, revokeToken, jti, expiresAt); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+
+	return nil
+}
+
+const isTokenRevoked = `
+	select exists(
+		select 1
+		from authgo.revoked_token
+		where jti=$1
+	);
+`
+
+func (l *revocationList) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+
+	conn, err := l.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, isTokenRevoked, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("check revoked token: %w", err)
+	}
+
+	return revoked, nil
+}
+
+const pruneRevokedTokens = `
+	delete from authgo.revoked_token
+	where expires_at < current_timestamp;
+`
+
+func (l *revocationList) Prune(ctx context.Context) error {
+	conn, err := l.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, pruneRevokedTokens); err != nil {
+		return fmt.Errorf("prune revoked tokens: %w", err)
+	}
+
+	return nil
+}
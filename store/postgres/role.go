@@ -41,12 +41,12 @@ func (s *roleStore) ApplyMigrations() error {
 }
 
 const insertOneRole = `
-	insert into authgo.role(name)
-	values ($1)
+	insert into authgo.role(name, display_name, description, is_system)
+	values ($1, $2, $3, $4)
 	returning id;
 `
 
-func (s *roleStore) InsertOne(ctx context.Context, name string) (int64, error) {
+func (s *roleStore) InsertOne(ctx context.Context, role model.RoleDao) (int64, error) {
 	var roleID int64
 
 	conn, err := s.pg.GetConn(ctx)
@@ -54,7 +54,15 @@ func (s *roleStore) InsertOne(ctx context.Context, name string) (int64, error) {
 		return 0, fmt.Errorf("get conn: %w", err)
 	}
 
-	if err := conn.QueryRow(ctx, insertOneRole, name).Scan(&roleID); err != nil {
+	err = conn.QueryRow(
+		ctx,
+		insertOneRole,
+		role.Name,
+		role.DisplayName,
+		role.Description,
+		role.IsSystem,
+	).Scan(&roleID)
+	if err != nil {
 		return 0, fmt.Errorf("insert role: %w", err)
 	}
 
@@ -62,7 +70,7 @@ func (s *roleStore) InsertOne(ctx context.Context, name string) (int64, error) {
 }
 
 const findOneRoleByID = `
-	select id, name, created_at
+	select id, name, display_name, description, is_system, created_at
 	from authgo.role
 	where id=$1;
 `
@@ -75,7 +83,14 @@ func (s *roleStore) FindOneByID(ctx context.Context, roleID int64) (model.RoleDa
 		return role, fmt.Errorf("get conn: %w", err)
 	}
 
-	if err := conn.QueryRow(ctx, findOneRoleByID, roleID).Scan(&role); err != nil {
+	if err := conn.QueryRow(ctx, findOneRoleByID, roleID).Scan(
+		&role.ID,
+		&role.Name,
+		&role.DisplayName,
+		&role.Description,
+		&role.IsSystem,
+		&role.CreatedAt,
+	); err != nil {
 		return role, fmt.Errorf("find role: %w", err)
 	}
 
@@ -83,7 +98,7 @@ func (s *roleStore) FindOneByID(ctx context.Context, roleID int64) (model.RoleDa
 }
 
 const findOneRoleByName = `
-	select id, name, created_at
+	select id, name, display_name, description, is_system, created_at
 	from authgo.role
 	where name=$1;
 `
@@ -99,6 +114,9 @@ func (s *roleStore) FindOneByName(ctx context.Context, name string) (model.RoleD
 	if err := conn.QueryRow(ctx, findOneRoleByName, name).Scan(
 		&role.ID,
 		&role.Name,
+		&role.DisplayName,
+		&role.Description,
+		&role.IsSystem,
 		&role.CreatedAt,
 	); err != nil {
 		return role, fmt.Errorf("find role: %w", err)
@@ -109,7 +127,7 @@ func (s *roleStore) FindOneByName(ctx context.Context, name string) (model.RoleD
 
 const updateOneRole = `
 	update authgo.role
-	set name=$2
+	set name=$2, display_name=$3, description=$4
 	where id=$1;
 `
 
@@ -124,6 +142,8 @@ func (s *roleStore) UpdateOne(ctx context.Context, role model.RoleDao) error {
 		updateOneRole,
 		role.ID,
 		role.Name,
+		role.DisplayName,
+		role.Description,
 	)
 	if err != nil {
 		return fmt.Errorf("update role data: %w", err)
@@ -136,7 +156,25 @@ func (s *roleStore) UpdateOne(ctx context.Context, role model.RoleDao) error {
 	return nil
 }
 
+// deleteOneRole also removes the role from pending invites and cancels pending access requests for it,
+// so accepting or approving them never grants a deleted role.
 const deleteOneRole = `
+	with deleted_assignment as (
+		delete from authgo.user_role
+		where role_id=$1
+	), updated_invite as (
+		update authgo.invite
+		set role_ids=array_remove(role_ids, $1)
+		where $1=any(role_ids) and accepted_at is null and revoked_at is null
+	), cancelled_access_request as (
+		update authgo.access_request
+		set status='cancelled', decided_at=current_timestamp
+		where role_id=$1 and status='pending'
+		returning id
+	), cancelled_access_request_event as (
+		insert into authgo.access_request_event(request_id, status, comment)
+		select id, 'cancelled', 'role is deleted' from cancelled_access_request
+	)
 	delete from authgo.role
 	where id=$1;
 `
@@ -163,8 +201,77 @@ func (s *roleStore) DeleteOne(ctx context.Context, roleID int64) error {
 	return nil
 }
 
+const countRoleAssignments = `
+	select
+		(select count(distinct user_id) from authgo.user_role
+			where role_id=$1 and (expires_at is null or expires_at > current_timestamp)) +
+		(select count(*) from authgo.org_user_role where role_id=$1) +
+		(select count(*) from authgo.role_inheritance where role_id=$1 or inherited_role_id=$1) +
+		(select count(*) from authgo.invite
+			where $1=any(role_ids) and accepted_at is null and revoked_at is null
+				and expires_at > current_timestamp) +
+		(select count(*) from authgo.access_request
+			where role_id=$1 and status='pending' and expires_at > current_timestamp);
+`
+
+func (s *roleStore) CountAssignments(ctx context.Context, roleID int64) (int64, error) {
+	var count int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, countRoleAssignments, roleID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count role assignments: %w", err)
+	}
+
+	return count, nil
+}
+
+const reassignUserRole = `
+	with moved as (
+		delete from authgo.user_role
+		where role_id=$1
+		returning user_id, expires_at, granted_by, reason
+	)
+	insert into authgo.user_role (user_id, role_id, expires_at, granted_by, reason)
+	select m.user_id, $2::bigint, m.expires_at, m.granted_by, m.reason from moved m
+	where not exists (
+		select 1 from authgo.user_role ur where ur.user_id=m.user_id and ur.role_id=$2
+	);
+`
+
+const reassignOrgUserRole = `
+	with moved as (
+		delete from authgo.org_user_role
+		where role_id=$1
+		returning org_id, user_id
+	)
+	insert into authgo.org_user_role (org_id, user_id, role_id)
+	select m.org_id, m.user_id, $2::bigint from moved m
+	on conflict do nothing;
+`
+
+func (s *roleStore) ReassignRole(ctx context.Context, fromRoleID, toRoleID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, reassignUserRole, fromRoleID, toRoleID); err != nil {
+		return fmt.Errorf("reassign user roles: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, reassignOrgUserRole, fromRoleID, toRoleID); err != nil {
+		return fmt.Errorf("reassign org user roles: %w", err)
+	}
+
+	return nil
+}
+
 const listAllRoles = `
-	select id, name, created_at
+	select id, name, display_name, description, is_system, created_at
 	from authgo.role;
 `
 
@@ -181,7 +288,14 @@ func (s *roleStore) ListAll(ctx context.Context) ([]model.RoleDao, error) {
 
 	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RoleDao, error) {
 		var role model.RoleDao
-		err := row.Scan(&role.ID, &role.Name, &role.CreatedAt)
+		err := row.Scan(
+			&role.ID,
+			&role.Name,
+			&role.DisplayName,
+			&role.Description,
+			&role.IsSystem,
+			&role.CreatedAt,
+		)
 		return role, err
 	})
 	if err != nil {
@@ -192,10 +306,23 @@ func (s *roleStore) ListAll(ctx context.Context) ([]model.RoleDao, error) {
 }
 
 const listUserRoles = `
-	select r.id, r.name, r.created_at from authgo.role r
-	join authgo.user_role ur
-		on ur.role_id = r.id
-	where ur.user_id = $1;
+	with recursive effective_role(role_id, expires_at) as (
+		select role_id, expires_at from authgo.user_role
+		where user_id = $1 and (expires_at is null or expires_at > current_timestamp)
+		union
+		select ri.inherited_role_id, er.expires_at from authgo.role_inheritance ri
+		join effective_role er
+			on er.role_id = ri.role_id
+	)
+	select
+		r.id,
+		r.name,
+		r.created_at,
+		case when bool_or(er.expires_at is null) then null else max(er.expires_at) end
+	from authgo.role r
+	join effective_role er
+		on er.role_id = r.id
+	group by r.id;
 `
 
 func (s *roleStore) ListUserRoles(ctx context.Context, userID int64) ([]model.RoleDao, error) {
@@ -209,6 +336,232 @@ func (s *roleStore) ListUserRoles(ctx context.Context, userID int64) ([]model.Ro
 		return nil, fmt.Errorf("list user roles: %w", err)
 	}
 
+	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RoleDao, error) {
+		var role model.RoleDao
+		err := row.Scan(&role.ID, &role.Name, &role.CreatedAt, &role.ExpiresAt)
+		return role, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+const grantRolePermission = `
+	with p as (
+		insert into authgo.permission(name)
+		values ($2)
+		on conflict (name) do update set name=excluded.name
+		returning id
+	)
+	insert into authgo.role_permission(role_id, permission_id)
+	select $1, id from p
+	on conflict do nothing;
+`
+
+func (s *roleStore) GrantPermission(ctx context.Context, roleID int64, permission string) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, grantRolePermission, roleID, permission); err != nil {
+		return fmt.Errorf("grant permission: %w", err)
+	}
+
+	return nil
+}
+
+const revokeRolePermission = `
+	delete from authgo.role_permission rp
+	using authgo.permission p
+	where rp.permission_id=p.id and rp.role_id=$1 and p.name=$2;
+`
+
+func (s *roleStore) RevokePermission(ctx context.Context, roleID int64, permission string) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, revokeRolePermission, roleID, permission)
+	if err != nil {
+		return fmt.Errorf("revoke permission: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("revoke permission: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const listRolePermissions = `
+	select p.id, p.name, p.description, p.created_at from authgo.permission p
+	join authgo.role_permission rp
+		on rp.permission_id = p.id
+	where rp.role_id = $1
+	order by p.name;
+`
+
+func (s *roleStore) ListRolePermissions(ctx context.Context, roleID int64) ([]model.PermissionDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listRolePermissions, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("list role permissions: %w", err)
+	}
+
+	permissions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PermissionDao, error) {
+		var permission model.PermissionDao
+		err := row.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+		return permission, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+const listUserPermissions = `
+	with recursive effective_role(role_id) as (
+		select role_id from authgo.user_role
+		where user_id = $1 and (expires_at is null or expires_at > current_timestamp)
+		union
+		select ri.inherited_role_id from authgo.role_inheritance ri
+		join effective_role er
+			on er.role_id = ri.role_id
+	)
+	select distinct p.id, p.name, p.description, p.created_at from authgo.permission p
+	join authgo.role_permission rp
+		on rp.permission_id = p.id
+	join effective_role er
+		on er.role_id = rp.role_id
+	order by p.name;
+`
+
+func (s *roleStore) ListUserPermissions(ctx context.Context, userID int64) ([]model.PermissionDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listUserPermissions, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user permissions: %w", err)
+	}
+
+	permissions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PermissionDao, error) {
+		var permission model.PermissionDao
+		err := row.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+		return permission, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// lockRoleInheritance serializes changes of inheritance until the end of transaction, otherwise two concurrent
+// transactions could each pass the cycle check under read committed and insert the opposite edges.
+const lockRoleInheritance = `
+	select pg_advisory_xact_lock(hashtext('authgo.role_inheritance'));
+`
+
+// addInheritedRole inserts inheritance unless the inherited role already implies the role, which would be a cycle.
+// Existing inheritance is touched, so it is counted as affected row.
+const addInheritedRole = `
+	insert into authgo.role_inheritance(role_id, inherited_role_id)
+	select $1::bigint, $2::bigint
+	where $1::bigint <> $2::bigint and not exists (
+		with recursive implied(role_id) as (
+			select $2::bigint
+			union
+			select ri.inherited_role_id from authgo.role_inheritance ri
+			join implied i
+				on i.role_id = ri.role_id
+		)
+		select 1 from implied
+		where role_id = $1::bigint
+	)
+	on conflict (role_id, inherited_role_id) do update
+	set created_at = authgo.role_inheritance.created_at;
+`
+
+// AddInheritedRole must be called within a transaction.
+func (s *roleStore) AddInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) (bool, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, lockRoleInheritance); err != nil {
+		return false, fmt.Errorf("lock role inheritance: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, addInheritedRole, roleID, inheritedRoleID)
+	if err != nil {
+		return false, fmt.Errorf("add inherited role: %w", err)
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+const removeInheritedRole = `
+	delete from authgo.role_inheritance
+	where role_id=$1 and inherited_role_id=$2;
+`
+
+func (s *roleStore) RemoveInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, removeInheritedRole, roleID, inheritedRoleID)
+	if err != nil {
+		return fmt.Errorf("remove inherited role: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("remove inherited role: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const listInheritedRoles = `
+	with recursive implied(role_id) as (
+		select inherited_role_id from authgo.role_inheritance
+		where role_id = $1
+		union
+		select ri.inherited_role_id from authgo.role_inheritance ri
+		join implied i
+			on i.role_id = ri.role_id
+	)
+	select r.id, r.name, r.created_at from authgo.role r
+	join implied i
+		on i.role_id = r.id
+	where r.id <> $1;
+`
+
+func (s *roleStore) ListInheritedRoles(ctx context.Context, roleID int64) ([]model.RoleDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listInheritedRoles, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("list inherited roles: %w", err)
+	}
+
 	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RoleDao, error) {
 		var role model.RoleDao
 		err := row.Scan(&role.ID, &role.Name, &role.CreatedAt)
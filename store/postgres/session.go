@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type sessionStore struct {
+	pg *postgresDB
+}
+
+// NewSessionStore creates an instance of SessionStore over postgres connection.
+func NewSessionStore(pg *postgresDB) *sessionStore {
+	return &sessionStore{
+		pg: pg,
+	}
+}
+
+func (s *sessionStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *sessionStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *sessionStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *sessionStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const insertOneSession = `
+	insert into authgo.session(id, user_id, user_agent, ip, expires_at)
+	values ($1, $2, $3, $4, $5);
+`
+
+func (s *sessionStore) InsertOne(ctx context.Context, session model.SessionDao) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(
+		ctx,
+		insertOneSession,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("insert session: %w", err)
+	}
+
+	return nil
+}
+
+const findOneSessionByID = `
+	select id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, ended_at
+	from authgo.session
+	where id=$1;
+`
+
+func (s *sessionStore) FindOneByID(ctx context.Context, sessionID string) (model.SessionDao, error) {
+	var session model.SessionDao
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return session, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, findOneSessionByID, sessionID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.EndedAt,
+	); err != nil {
+		return session, fmt.Errorf("find session: %w", err)
+	}
+
+	return session, nil
+}
+
+const touchSession = `
+	update authgo.session
+	set last_seen_at=current_timestamp
+	where id=$1 and ended_at is null;
+`
+
+func (s *sessionStore) Touch(ctx context.Context, sessionID string) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, touchSession, sessionID)
+	if err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("touch session: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const endSession = `
+	update authgo.session
+	set ended_at=current_timestamp
+	where id=$1 and ended_at is null;
+`
+
+func (s *sessionStore) End(ctx context.Context, sessionID string) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, endSession, sessionID)
+	if err != nil {
+		return fmt.Errorf("end session: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("end session: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+const endAllUserSessions = `
+	update authgo.session
+	set ended_at=current_timestamp
+	where user_id=$1 and ended_at is null;
+`
+
+func (s *sessionStore) EndAllUserSessions(ctx context.Context, userID int64) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, endAllUserSessions, userID); err != nil {
+		return fmt.Errorf("end all user sessions: %w", err)
+	}
+
+	return nil
+}
+
+const listUserSessions = `
+	select id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, ended_at
+	from authgo.session
+	where user_id=$1 and ended_at is null and expires_at > current_timestamp
+	order by last_seen_at desc;
+`
+
+func (s *sessionStore) ListUserSessions(ctx context.Context, userID int64) ([]model.SessionDao, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, listUserSessions, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user sessions: %w", err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.SessionDao, error) {
+		var session model.SessionDao
+		err := row.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+			&session.EndedAt,
+		)
+		return session, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect sessions: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type refreshTokenStore struct {
+	pg *postgresDB
+}
+
+// NewRefreshTokenStore creates an instance of RefreshTokenStore over postgres connection.
+func NewRefreshTokenStore(pg *postgresDB) *refreshTokenStore {
+	return &refreshTokenStore{
+		pg: pg,
+	}
+}
+
+func (s *refreshTokenStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *refreshTokenStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *refreshTokenStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *refreshTokenStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+const insertOneRefreshToken = `
+	insert into authgo.refresh_token(user_id, session_id, org_id, family_id, token_hash, expires_at)
+	values ($1, $2, $3, $4, $5, $6)
+	returning id;
+`
+
+func (s *refreshTokenStore) InsertOne(ctx context.Context, token model.RefreshTokenDao) (int64, error) {
+	var tokenID int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	err = conn.QueryRow(
+		ctx,
+		insertOneRefreshToken,
+		token.UserID,
+		token.SessionID,
+		token.OrgID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&tokenID)
+	if err != nil {
+		return 0, fmt.Errorf("insert refresh token: %w", err)
+	}
+
+	return tokenID, nil
+}
+
+const findOneRefreshTokenByHash = `
+	select id, user_id, session_id, org_id, family_id, token_hash, created_at, expires_at, used_at, revoked_at
+	from authgo.refresh_token
+	where token_hash=$1;
+`
+
+func (s *refreshTokenStore) FindOneByHash(ctx context.Context, tokenHash string) (model.RefreshTokenDao, error) {
+	var token model.RefreshTokenDao
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return token, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, findOneRefreshTokenByHash, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.SessionID,
+		&token.OrgID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+	); err != nil {
+		return token, fmt.Errorf("find refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+const markRefreshTokenUsed = `
+	update authgo.refresh_token
+	set used_at=current_timestamp
+	where id=$1 and used_at is null and revoked_at is null;
+`
+
+func (s *refreshTokenStore) MarkUsed(ctx context.Context, tokenID int64) (bool, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get conn: %w", err)
+	}
+
+	res, err := conn.Exec(ctx, markRefreshTokenUsed, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("mark refresh token used: %w", err)
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+const revokeRefreshTokenFamily = `
+	update authgo.refresh_token
+	set revoked_at=current_timestamp
+	where family_id=$1 and revoked_at is null;
+`
+
+func (s *refreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, revokeRefreshTokenFamily, familyID); err != nil {
+		return fmt.Errorf("revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
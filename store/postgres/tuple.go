@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yogenyslav/authgo/db"
+	"github.com/yogenyslav/authgo/model"
+)
+
+type tupleStore struct {
+	pg *postgresDB
+}
+
+// NewTupleStore creates an instance of TupleStore over postgres connection.
+func NewTupleStore(pg *postgresDB) *tupleStore {
+	return &tupleStore{
+		pg: pg,
+	}
+}
+
+func (s *tupleStore) StartTx(ctx context.Context) (context.Context, error) {
+	return s.pg.StartTx(ctx)
+}
+
+func (s *tupleStore) CommitTx(ctx context.Context) error {
+	return s.pg.CommitTx(ctx)
+}
+
+func (s *tupleStore) RollbackTx(ctx context.Context) error {
+	return s.pg.RollbackTx(ctx)
+}
+
+func (s *tupleStore) ApplyMigrations() error {
+	if err := db.ApplyMigrations("postgres", db.PgMigrations, stdlib.OpenDBFromPool(s.pg.GetPool())); err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	return nil
+}
+
+// lockTupleWrites serializes writers until the end of transaction, so revisions are committed in order
+// and a reader never observes revision N before revision N-1.
+const lockTupleWrites = `
+	select pg_advisory_xact_lock(hashtext('authgo.relation_tuple'));
+`
+
+const insertTupleRevision = `
+	insert into authgo.relation_tuple_revision default values
+	returning revision;
+`
+
+const deleteTuple = `
+	update authgo.relation_tuple
+	set deleted_revision=$7
+	where namespace=$1 and object_id=$2 and relation=$3
+		and subject_namespace=$4 and subject_id=$5 and subject_relation=$6
+		and deleted_revision is null;
+`
+
+const insertTuple = `
+	insert into authgo.relation_tuple(
+		namespace, object_id, relation, subject_namespace, subject_id, subject_relation, created_revision
+	)
+	values ($1, $2, $3, $4, $5, $6, $7)
+	on conflict (namespace, object_id, relation, subject_namespace, subject_id, subject_relation)
+		where deleted_revision is null
+		do nothing;
+`
+
+// Write must be called within a transaction.
+func (s *tupleStore) Write(ctx context.Context, inserts, deletes []model.RelationTuple) (int64, error) {
+	var revision int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, lockTupleWrites); err != nil {
+		return 0, fmt.Errorf("lock tuples: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, insertTupleRevision).Scan(&revision); err != nil {
+		return 0, fmt.Errorf("insert revision: %w", err)
+	}
+
+	for _, t := range deletes {
+		if _, err := conn.Exec(ctx, deleteTuple, tupleArgs(t, revision)...); err != nil {
+			return 0, fmt.Errorf("delete tuple %s: %w", t, err)
+		}
+	}
+
+	for _, t := range inserts {
+		if _, err := conn.Exec(ctx, insertTuple, tupleArgs(t, revision)...); err != nil {
+			return 0, fmt.Errorf("insert tuple %s: %w", t, err)
+		}
+	}
+
+	return revision, nil
+}
+
+func tupleArgs(t model.RelationTuple, revision int64) []any {
+	return []any{
+		t.Object.Namespace,
+		t.Object.ID,
+		t.Relation,
+		t.Subject.Namespace,
+		t.Subject.ID,
+		t.Subject.Relation,
+		revision,
+	}
+}
+
+const latestTupleRevision = `
+	select coalesce(max(revision), 0)
+	from authgo.relation_tuple_revision;
+`
+
+func (s *tupleStore) Revision(ctx context.Context) (int64, error) {
+	var revision int64
+
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get conn: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, latestTupleRevision).Scan(&revision); err != nil {
+		return 0, fmt.Errorf("latest revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+const readTupleSubjects = `
+	select subject_namespace, subject_id, subject_relation
+	from authgo.relation_tuple
+	where namespace=$1 and object_id=$2 and relation=$3
+		and created_revision <= $4 and (deleted_revision is null or deleted_revision > $4);
+`
+
+func (s *tupleStore) ReadSubjects(
+	ctx context.Context,
+	object model.Object,
+	relation string,
+	revision int64,
+) ([]model.Subject, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, readTupleSubjects, object.Namespace, object.ID, relation, revision)
+	if err != nil {
+		return nil, fmt.Errorf("read subjects: %w", err)
+	}
+
+	subjects, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Subject, error) {
+		var subject model.Subject
+		err := row.Scan(&subject.Namespace, &subject.ID, &subject.Relation)
+		return subject, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect subjects: %w", err)
+	}
+
+	return subjects, nil
+}
+
+const readTuplesBySubject = `
+	select namespace, object_id, relation
+	from authgo.relation_tuple
+	where subject_namespace=$1 and subject_id=$2 and subject_relation=$3
+		and created_revision <= $4 and (deleted_revision is null or deleted_revision > $4);
+`
+
+func (s *tupleStore) ReadBySubject(
+	ctx context.Context,
+	subject model.Subject,
+	revision int64,
+) ([]model.RelationTuple, error) {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get conn: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, readTuplesBySubject, subject.Namespace, subject.ID, subject.Relation, revision)
+	if err != nil {
+		return nil, fmt.Errorf("read tuples by subject: %w", err)
+	}
+
+	tuples, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RelationTuple, error) {
+		tuple := model.RelationTuple{Subject: subject}
+		err := row.Scan(&tuple.Object.Namespace, &tuple.Object.ID, &tuple.Relation)
+		return tuple, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect tuples: %w", err)
+	}
+
+	return tuples, nil
+}
@@ -242,7 +242,9 @@ func (s *userStore) ListAll(ctx context.Context) ([]model.UserDao, error) {
 
 const setRole = `
 	insert into authgo.user_role (user_id, role_id)
-	values ($1, $2);
+	values ($1, $2)
+	on conflict (user_id, role_id) do update
+	set expires_at=null;
 `
 
 func (s *userStore) SetRole(ctx context.Context, userID, roleID int64) error {
@@ -258,6 +260,37 @@ func (s *userStore) SetRole(ctx context.Context, userID, roleID int64) error {
 	return nil
 }
 
+// setRoleUntil never replaces a permanent grant with a time-bound one.
+const setRoleUntil = `
+	insert into authgo.user_role (user_id, role_id, expires_at, granted_by, reason)
+	values ($1, $2, $3, $4, $5)
+	on conflict (user_id, role_id) do update
+	set expires_at=excluded.expires_at, granted_by=excluded.granted_by, reason=excluded.reason
+	where user_role.expires_at is not null;
+`
+
+func (s *userStore) SetRoleUntil(ctx context.Context, grant model.RoleGrant) error {
+	conn, err := s.pg.GetConn(ctx)
+	if err != nil {
+		return fmt.Errorf("get conn: %w", err)
+	}
+
+	_, err = conn.Exec(
+		ctx,
+		setRoleUntil,
+		grant.UserID,
+		grant.RoleID,
+		grant.ExpiresAt,
+		grant.GrantedBy,
+		grant.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("insert user role grant: %w", err)
+	}
+
+	return nil
+}
+
 const removeRole = `
 	delete from authgo.user_role
 	where user_id=$1 and role_id=$2;
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationList holds ids (jti) of access tokens that were revoked before their expiration.
+type RevocationList interface {
+	// Revoke adds token id to the list until expiresAt, which is the last moment the token can be accepted
+	// (its exp claim plus the allowed leeway).
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsRevoked reports whether token id is in the list.
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// Prune removes ids of tokens that are already expired.
+	Prune(ctx context.Context) error
+}
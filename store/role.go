@@ -10,17 +10,40 @@ import (
 type RoleStore interface {
 	Store
 	// InsertOne creates new role.
-	InsertOne(ctx context.Context, name string) (int64, error)
+	InsertOne(ctx context.Context, role model.RoleDao) (int64, error)
 	// FindOneByID finds a role by its id.
 	FindOneByID(ctx context.Context, roleID int64) (model.RoleDao, error)
 	// FindOneByName finds a role by its name.
 	FindOneByName(ctx context.Context, name string) (model.RoleDao, error)
-	// UpdateOne updates a role.
+	// UpdateOne updates name, display name and description of a role.
 	UpdateOne(ctx context.Context, role model.RoleDao) error
-	// DeleteOne deletes a role.
+	// DeleteOne deletes a role with all of its assignments, grants and inheritance,
+	// the role is also removed from pending invites and pending access requests for it are cancelled.
 	DeleteOne(ctx context.Context, roleID int64) error
+	// CountAssignments returns a number of references to a role: users holding it globally or within organizations,
+	// roles inheriting it or inherited by it, pending invites and pending access requests for it.
+	CountAssignments(ctx context.Context, roleID int64) (int64, error)
+	// ReassignRole assigns a role to all holders of another role and removes the latter from them.
+	ReassignRole(ctx context.Context, fromRoleID, toRoleID int64) error
 	// ListAll returns a list of all existing roles.
 	ListAll(ctx context.Context) ([]model.RoleDao, error)
-	// ListUserRoles returns a list of all roles asigned to a certain user.
+	// ListUserRoles returns a list of all roles asigned to a certain user, including inherited ones.
+	// Expired grants are skipped, roles held only by time-bound grants have ExpiresAt set.
 	ListUserRoles(ctx context.Context, userID int64) ([]model.RoleDao, error)
+	// AddInheritedRole makes holders of the role also hold inherited role.
+	// It returns false if the inheritance would create a cycle, must be called within a transaction.
+	AddInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) (bool, error)
+	// RemoveInheritedRole removes inheritance between roles.
+	RemoveInheritedRole(ctx context.Context, roleID, inheritedRoleID int64) error
+	// ListInheritedRoles returns a list of all roles inherited by a certain role directly or transitively.
+	ListInheritedRoles(ctx context.Context, roleID int64) ([]model.RoleDao, error)
+	// GrantPermission grants permission to a role, the permission is created if it doesn't exist.
+	GrantPermission(ctx context.Context, roleID int64, permission string) error
+	// RevokePermission revokes permission from a role.
+	RevokePermission(ctx context.Context, roleID int64, permission string) error
+	// ListRolePermissions returns a list of permissions granted to a certain role.
+	ListRolePermissions(ctx context.Context, roleID int64) ([]model.PermissionDao, error)
+	// ListUserPermissions returns a list of permissions granted to a certain user by all of the user roles,
+	// including inherited ones, expired grants are skipped.
+	ListUserPermissions(ctx context.Context, userID int64) ([]model.PermissionDao, error)
 }
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// SessionStore provides methods to manipulate with server-side auth sessions.
+type SessionStore interface {
+	Store
+	// InsertOne creates a new session.
+	InsertOne(ctx context.Context, session model.SessionDao) error
+	// FindOneByID finds a session by its id.
+	FindOneByID(ctx context.Context, sessionID string) (model.SessionDao, error)
+	// Touch updates the last time the session was seen.
+	Touch(ctx context.Context, sessionID string) error
+	// End ends a session, so it can't be used anymore.
+	End(ctx context.Context, sessionID string) error
+	// EndAllUserSessions ends every active session of a user.
+	EndAllUserSessions(ctx context.Context, userID int64) error
+	// ListUserSessions returns a list of active sessions of a user.
+	ListUserSessions(ctx context.Context, userID int64) ([]model.SessionDao, error)
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// RefreshTokenStore provides methods to persist and rotate refresh tokens.
+type RefreshTokenStore interface {
+	Store
+	// InsertOne creates a new refresh token record.
+	InsertOne(ctx context.Context, token model.RefreshTokenDao) (int64, error)
+	// FindOneByHash finds a refresh token by the hash of its raw value.
+	FindOneByHash(ctx context.Context, tokenHash string) (model.RefreshTokenDao, error)
+	// MarkUsed marks a refresh token as used and reports whether it was unused before the call.
+	MarkUsed(ctx context.Context, tokenID int64) (bool, error)
+	// RevokeFamily revokes every refresh token that belongs to the family.
+	RevokeFamily(ctx context.Context, familyID string) error
+}
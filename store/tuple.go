@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/yogenyslav/authgo/model"
+)
+
+// TupleStore provides methods to manipulate with relation tuples. Tuples are versioned:
+// every write creates a new revision, and reads return tuples as they were at the given revision.
+type TupleStore interface {
+	Store
+	// Write atomically inserts and deletes tuples and returns revision of the change.
+	// Inserting existing and deleting missing tuples is not an error.
+	Write(ctx context.Context, inserts, deletes []model.RelationTuple) (int64, error)
+	// Revision returns the latest revision.
+	Revision(ctx context.Context) (int64, error)
+	// ReadSubjects returns subjects having the relation to the object at revision.
+	ReadSubjects(ctx context.Context, object model.Object, relation string, revision int64) ([]model.Subject, error)
+	// ReadBySubject returns tuples with the subject at revision.
+	ReadBySubject(ctx context.Context, subject model.Subject, revision int64) ([]model.RelationTuple, error)
+}
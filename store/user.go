@@ -21,8 +21,11 @@ type UserStore interface {
 	DeleteOne(ctx context.Context, userID int64) error
 	// ListAll return the list of all existing users.
 	ListAll(ctx context.Context) ([]model.UserDao, error)
-	// SetRole assigns role to user.
+	// SetRole assigns role to user permanently, an existing time-bound grant of the role becomes permanent.
 	SetRole(ctx context.Context, userID, roleID int64) error
+	// SetRoleUntil assigns role to user until expiration, an existing time-bound grant of the role is replaced,
+	// a permanent one is kept.
+	SetRoleUntil(ctx context.Context, grant model.RoleGrant) error
 	// RemoveRole removes role from user.
 	RemoveRole(ctx context.Context, userID, roleID int64) error
 }